@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscolkdo/breach-protocol/game"
+	"github.com/franciscolkdo/breach-protocol/game/generator"
+	"github.com/spf13/cobra"
+)
+
+var arcadeSeed int64
+
+// arcadeCmd represents the arcade command
+var arcadeCmd = &cobra.Command{
+	Use:   "arcade",
+	Short: "Play an endless run of generated breaches",
+	Long: `Play generated breaches one after the other, each level is harder than the previous one:
+bigger matrix, more symbols, less free slots in the buffer and less time.
+The run ends on the first failed breach, showing the score and the level reached.
+To replay a run, use the -s option with the seed shown on the end screen.
+To watch the run later, record it with the -r option and play it back with the replay command.
+	`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cmd.Flags().Changed("seed") {
+			arcadeSeed = time.Now().UnixNano()
+		}
+		g := game.NewGame(generator.Arcade{Seed: arcadeSeed}, arcadeSeed).WithScores("arcade", "arcade")
+		if recordPath != "" {
+			g = g.WithRecording(game.Replay{Mode: arcadeMode, Start: game.Save{Campaign: "arcade", Seed: arcadeSeed}})
+		}
+
+		m, err := tea.NewProgram(g, tea.WithMouseAllMotion()).Run()
+		if err != nil {
+			return err
+		}
+		return writeReplay(m)
+	},
+}
+
+func init() {
+	arcadeCmd.Flags().Int64VarP(&arcadeSeed, "seed", "s", 0, "seed of the run, random if not set")
+	addRecordFlag(arcadeCmd)
+	rootCmd.AddCommand(arcadeCmd)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscolkdo/breach-protocol/game"
+	"github.com/franciscolkdo/breach-protocol/game/generator"
+	"github.com/franciscolkdo/breach-protocol/storage"
+	"github.com/spf13/cobra"
+)
+
+// dailyFile is the file of the data directory keeping daily results
+const dailyFile = "daily.json"
+
+var dailyPractice bool
+
+// dailyRecord is the result of a daily challenge
+type dailyRecord struct {
+	Score     int  `json:"score"`
+	Level     int  `json:"level"`
+	Completed bool `json:"completed"`
+}
+
+// dailyCmd represents the daily command
+var dailyCmd = &cobra.Command{
+	Use:   "daily",
+	Short: "Play the daily challenge",
+	Long: `Play the three breaches of the day, everyone gets the same puzzles on the same UTC date.
+The result is recorded locally, only the first attempt of the day is scored.
+Use the --practice option to play again without recording the result.
+To watch the run later, record it with the -r option and play it back with the replay command.
+	`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		now := time.Now().UTC()
+		date := now.Format(time.DateOnly)
+		records := make(map[string]dailyRecord)
+		if err := storage.Read(dailyFile, &records); err != nil {
+			return err
+		}
+		record, played := records[date]
+		if played && !dailyPractice {
+			return fmt.Errorf("daily challenge of %s already played with a score of %d, use --practice to play again", date, record.Score)
+		}
+		levels, err := generator.Daily(now)
+		if err != nil {
+			return fmt.Errorf("error on generating daily challenge: %w", err)
+		}
+		// Attempt is recorded before playing, quitting the game does not give a new attempt
+		if !dailyPractice {
+			records[date] = dailyRecord{}
+			if err := storage.Write(dailyFile, records); err != nil {
+				return err
+			}
+		}
+
+		g := game.NewGame(levels, generator.DailySeed(now)).WithoutRetry()
+		if !dailyPractice {
+			g = g.WithScores("daily-"+date, "daily "+date)
+		}
+		if recordPath != "" {
+			g = g.WithRecording(game.Replay{Mode: dailyMode, Date: date, Start: game.Save{Campaign: "daily-" + date, Seed: generator.DailySeed(now)}})
+		}
+		m, err := tea.NewProgram(g, tea.WithMouseAllMotion()).Run()
+		if err != nil {
+			return err
+		}
+		if dailyPractice {
+			return writeReplay(m)
+		}
+		// Result is stored before the replay, a replay error does not lose the attempt
+		res := m.(game.Model).GetResult()
+		records[date] = dailyRecord{Score: res.Score, Level: res.Level, Completed: res.Completed}
+		if err := storage.Write(dailyFile, records); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Daily challenge of %s: score %d, %d/%d breaches\n", date, res.Score, res.Level, levels.Len())
+		return writeReplay(m)
+	},
+}
+
+func init() {
+	dailyCmd.Flags().BoolVarP(&dailyPractice, "practice", "p", false, "play without recording the result")
+	addRecordFlag(dailyCmd)
+	rootCmd.AddCommand(dailyCmd)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/franciscolkdo/breach-protocol/game/generator"
+	"github.com/spf13/cobra"
+)
+
+var generateOpts generator.Options
+var generateOutput string
+
+// generateCmd represents the generate command
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a campaign file",
+	Long: fmt.Sprintf(`Generate a campaign of breach levels, each one preceded by a story placeholder to fill.
+Levels get harder along the campaign following the difficulty curve: %v.
+Each level is checked with the solver to be winnable, the output can be played with start -c.
+	`, generator.Difficulties),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cmd.Flags().Changed("seed") {
+			generateOpts.Seed = time.Now().UnixNano()
+		}
+		cfg, err := generator.Generate(generateOpts)
+		if err != nil {
+			return fmt.Errorf("error on generating campaign: %w", err)
+		}
+		data, err := json.MarshalIndent(cfg, "", "    ")
+		if err != nil {
+			return fmt.Errorf("error on encoding campaign: %w", err)
+		}
+
+		var w io.Writer = cmd.OutOrStdout()
+		if generateOutput != "" && generateOutput != "-" {
+			f, err := os.Create(generateOutput)
+			if err != nil {
+				return fmt.Errorf("error on creating campaign file: %w", err)
+			}
+			defer f.Close()
+			w = f
+		}
+		if _, err := fmt.Fprintln(w, string(data)); err != nil {
+			return fmt.Errorf("error on writing campaign: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	generateCmd.Flags().IntVarP(&generateOpts.Levels, "levels", "n", 5, "number of breach levels")
+	generateCmd.Flags().StringVarP((*string)(&generateOpts.Difficulty), "difficulty", "d", string(generator.Normal), "difficulty curve of the campaign")
+	generateCmd.Flags().Int64VarP(&generateOpts.Seed, "seed", "s", 0, "seed of the generated levels, random if not set")
+	generateCmd.Flags().StringVarP(&generateOutput, "output", "o", "", "file to write, stdout if not set")
+	rootCmd.AddCommand(generateCmd)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscolkdo/breach-protocol/config"
+	"github.com/franciscolkdo/breach-protocol/game"
+	"github.com/franciscolkdo/breach-protocol/game/generator"
+	"github.com/franciscolkdo/breach-protocol/game/model"
+	"github.com/spf13/cobra"
+)
+
+// Commands which can record a run, kept in the replay to build the same levels
+const (
+	startMode  = "start"
+	arcadeMode = "arcade"
+	dailyMode  = "daily"
+)
+
+// maxReplaySpeed is the fastest replay, inputs must still be handled between two clock ticks
+const maxReplaySpeed = 10
+
+var recordPath string
+var replayConfig string
+var replaySpeed float64
+
+// addRecordFlag add the option to record the run of the command
+func addRecordFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&recordPath, "record", "r", "", "file to record the run in, to watch it with the replay command")
+}
+
+// writeReplay write the run of the ended game in the record file, if recorded
+func writeReplay(m tea.Model) error {
+	r, ok := m.(game.Model).GetReplay()
+	if !ok {
+		return nil
+	}
+	return r.Write(recordPath)
+}
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay <file>",
+	Short: "Watch a recorded run",
+	Long: `Play back a run recorded with the --record option of the start, arcade and daily commands.
+The run is played with the same seed and the same inputs, use the -x option to watch it faster.
+A campaign run needs the campaign file it was recorded with, use the -c option if the file has moved.
+	`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if replaySpeed <= 0 || replaySpeed > maxReplaySpeed {
+			return fmt.Errorf("speed should be between 0 and %d, got %g", maxReplaySpeed, replaySpeed)
+		}
+		r, err := game.ReadReplay(args[0])
+		if err != nil {
+			return err
+		}
+		levels, lives, err := replayLevels(r)
+		if err != nil {
+			return err
+		}
+		g := game.NewGame(levels, r.Start.Seed).WithLives(lives)
+		if r.Mode == dailyMode {
+			g = g.WithoutRetry()
+		}
+		g = g.WithReplay(r, replaySpeed)
+
+		_, err = tea.NewProgram(g, tea.WithMouseAllMotion()).Run()
+		return err
+	},
+}
+
+// replayLevels return the levels and the lives of the recorded run
+func replayLevels(r game.Replay) (model.Levels, int, error) {
+	switch r.Mode {
+	case startMode:
+		path := r.Start.Path
+		if replayConfig != "" {
+			path = replayConfig
+		}
+		cfg, err := config.GetConfig(path)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error on reading config file: %w", err)
+		}
+		if cfg.Hash != r.Start.Campaign {
+			return nil, 0, fmt.Errorf("campaign file has changed since the run was recorded")
+		}
+		return model.Campaign(cfg.Models), cfg.Lives, nil
+	case arcadeMode:
+		return generator.Arcade{Seed: r.Start.Seed}, 0, nil
+	case dailyMode:
+		date, err := time.Parse(time.DateOnly, r.Date)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error on reading daily date: %w", err)
+		}
+		levels, err := generator.Daily(date)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error on generating daily challenge: %w", err)
+		}
+		return levels, 0, nil
+	}
+	return nil, 0, fmt.Errorf("unknown replay mode %q", r.Mode)
+}
+
+func init() {
+	replayCmd.Flags().StringVarP(&replayConfig, "config", "c", "", "campaign file of the run, the recorded path if not set")
+	replayCmd.Flags().Float64VarP(&replaySpeed, "speed", "x", 1, fmt.Sprintf("speed of the replay, up to %d", maxReplaySpeed))
+	rootCmd.AddCommand(replayCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/franciscolkdo/breach-protocol/game/highscore"
+	"github.com/spf13/cobra"
+)
+
+var scoresCampaign string
+var scoresLevel int
+
+// scoresCmd represents the scores command
+var scoresCmd = &cobra.Command{
+	Use:   "scores",
+	Short: "Show the high scores",
+	Long: `Show the best runs of each campaign, or the best results of a level with the -l option.
+Levels are numbered from 1 like models in the campaign file, stories included.
+Use the -c option to show only one campaign, by name or hash.
+	`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		records, err := highscore.Read()
+		if err != nil {
+			return fmt.Errorf("error on reading high scores: %w", err)
+		}
+		level := highscore.Campaign
+		if scoresLevel > 0 {
+			level = scoresLevel - 1
+		}
+		// Campaigns are shown by name
+		campaigns := make(map[string]string)
+		for _, r := range records {
+			if scoresCampaign == "" || r.Campaign == scoresCampaign || r.Name == scoresCampaign {
+				campaigns[r.Campaign] = r.Name
+			}
+		}
+		hashes := make([]string, 0, len(campaigns))
+		for hash := range campaigns {
+			hashes = append(hashes, hash)
+		}
+		sort.Slice(hashes, func(i, j int) bool { return campaigns[hashes[i]] < campaigns[hashes[j]] })
+
+		out := cmd.OutOrStdout()
+		if len(hashes) == 0 {
+			fmt.Fprintln(out, "No high score yet")
+		}
+		for _, hash := range hashes {
+			best := highscore.Filter(records, hash, level)
+			if len(best) == 0 {
+				continue
+			}
+			if len(best) > highscore.Top {
+				best = best[:highscore.Top]
+			}
+			fmt.Fprintf(out, "%s (%.8s)\n", campaigns[hash], hash)
+			fmt.Fprintf(out, "%3s %6s %6s %6s  %-10s\n", "#", "Score", "Time", "Buffer", "Date")
+			for i, r := range best {
+				fmt.Fprintf(out, "%3d %6d %6s %6d  %-10s\n", i+1, r.Score, r.Remaining.Round(time.Second), r.Buffer, r.Date.Format(time.DateOnly))
+			}
+			fmt.Fprintln(out)
+		}
+		return nil
+	},
+}
+
+func init() {
+	scoresCmd.Flags().StringVarP(&scoresCampaign, "campaign", "c", "", "campaign to show, by name or hash")
+	scoresCmd.Flags().IntVarP(&scoresLevel, "level", "l", 0, "level to show, whole campaign runs if not set")
+	rootCmd.AddCommand(scoresCmd)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/franciscolkdo/breach-protocol/game/solver"
+	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
+)
+
+// solveCmd represents the solve command
+var solveCmd = &cobra.Command{
+	Use:   "solve [file]",
+	Short: "Solve a breach puzzle",
+	Long: `Solve a breach puzzle described in a json file, or read from stdin if no file is given.
+The puzzle gives the matrix grid, buffer size and sequences to upload:
+
+{
+  "matrix": [["55", "BD", "E9"], ["7A", "1C", "55"], ["BD", "E9", "7A"]],
+  "buffer": 4,
+  "sequences": [{"symbols": ["BD", "7A"], "points": 30, "description": "Extract data"}]
+}
+
+Picks are given as row and column, starting at 1 on the first row.
+	`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var r io.Reader = os.Stdin
+		if len(args) > 0 && args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("error on opening puzzle file: %w", err)
+			}
+			defer f.Close()
+			r = f
+		}
+		puzzle, err := solver.ReadPuzzle(r)
+		if err != nil {
+			return err
+		}
+		sol, err := puzzle.Solve()
+		if err != nil {
+			return fmt.Errorf("error on loading puzzle: %w", err)
+		}
+
+		out := cmd.OutOrStdout()
+		var picks, buffer []string
+		for i, c := range sol.Path {
+			picks = append(picks, fmt.Sprintf("(%d,%d)", c.Y+1, c.X+1))
+			buffer = append(buffer, sol.Buffer[i].String())
+		}
+		fmt.Fprintf(out, "Picks:  %s\n", strings.Join(picks, " "))
+		fmt.Fprintf(out, "Buffer: %s\n", strings.Join(buffer, " "))
+		fmt.Fprintln(out, "Sequences:")
+		total := 0
+		for i, seq := range puzzle.Sequences {
+			total += seq.Points
+			status := "[ ]"
+			if slices.Contains(sol.Uploaded, i) {
+				status = "[x]"
+			}
+			fmt.Fprintf(out, "  %s %s (%d points) %s\n", status, strings.Join(seq.Symbols, " "), seq.Points, seq.Description)
+		}
+		fmt.Fprintf(out, "Points: %d/%d\n", sol.Points, total)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(solveCmd)
+}
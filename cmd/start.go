@@ -5,14 +5,19 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/franciscolkdo/breach-protocol/config"
 	"github.com/franciscolkdo/breach-protocol/game"
+	"github.com/franciscolkdo/breach-protocol/game/model"
 	"github.com/spf13/cobra"
 )
 
 var configPath string
+var seed int64
+var continueRun bool
 
 // startCmd represents the start command
 var startCmd = &cobra.Command{
@@ -20,24 +25,67 @@ var startCmd = &cobra.Command{
 	Short: "Start the game!",
 	Long: `Start the breach-protocol game, it will look into /config/game.json by default
 If you want to provide a specific path for the config, use the -c option.
+To replay a run, use the -s option with the seed shown on the end screen.
+The progress is saved each time a level is done, use the --continue option to resume the last campaign.
+To watch the run later, record it with the -r option and play it back with the replay command.
 	`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		var save game.Save
+		if continueRun {
+			s, ok, err := game.ReadSave()
+			if err != nil {
+				return fmt.Errorf("error on reading save: %w", err)
+			}
+			if !ok {
+				return fmt.Errorf("no saved campaign to continue")
+			}
+			save = s
+			if !cmd.Flags().Changed("config") {
+				configPath = save.Path
+			}
+		}
 		cfg, err := config.GetConfig(configPath)
 		if err != nil {
 			return fmt.Errorf("error on reading config file: %s", err)
 		}
-		g := game.NewGame(cfg.Models)
+		if continueRun {
+			if save.Campaign != cfg.Hash {
+				return fmt.Errorf("campaign file has changed since the save, start a new campaign without --continue")
+			}
+		} else {
+			if !cmd.Flags().Changed("seed") {
+				seed = time.Now().UnixNano()
+			}
+			save = game.Save{Campaign: cfg.Hash, Seed: seed}
+			if configPath != "" {
+				if save.Path, err = filepath.Abs(configPath); err != nil {
+					return fmt.Errorf("error on reading config path: %w", err)
+				}
+			}
+		}
+		name := "default"
+		if save.Path != "" {
+			name = filepath.Base(save.Path)
+		}
+		g := game.NewGame(model.Campaign(cfg.Models), save.Seed).WithLives(cfg.Lives).WithScores(cfg.Hash, name).WithSave(save)
+		if recordPath != "" {
+			g = g.WithRecording(game.Replay{Mode: startMode, Start: save})
+		}
 
-		_, err = tea.NewProgram(g, tea.WithMouseCellMotion()).Run()
+		m, err := tea.NewProgram(g, tea.WithMouseAllMotion()).Run()
 		if err != nil {
 			return err
 		}
 
-		return nil
+		return writeReplay(m)
 	},
 }
 
 func init() {
 	startCmd.Flags().StringVarP(&configPath, "config", "c", "", "config file to use")
+	startCmd.Flags().Int64VarP(&seed, "seed", "s", 0, "seed of the run, random if not set")
+	startCmd.Flags().BoolVar(&continueRun, "continue", false, "resume the saved campaign")
+	startCmd.MarkFlagsMutuallyExclusive("continue", "seed")
+	addRecordFlag(startCmd)
 	rootCmd.AddCommand(startCmd)
 }
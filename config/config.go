@@ -1,6 +1,7 @@
 package config
 
 import (
+	"crypto/sha256"
 	_ "embed"
 	"encoding/json"
 
@@ -15,6 +16,8 @@ var configData []byte
 
 type Config struct {
 	Models []model.Config `json:"models"`
+	Lives  int            `json:"lives"` // Failed breaches allowed before game over, each one is retried, disabled if not set
+	Hash   string         `json:"-"`     // Hash of the config file, to know if it has changed
 }
 
 // NewGameConfig
@@ -30,5 +33,14 @@ func GetConfig(path string) (Config, error) {
 	if err != nil {
 		return Config{}, fmt.Errorf("error on unmarshal config data: %s", err)
 	}
+	cfg.Hash = fmt.Sprintf("%x", sha256.Sum256(configData))
+	if cfg.Lives < 0 {
+		return Config{}, fmt.Errorf("lives should not be negative, got %d", cfg.Lives)
+	}
+	for i, m := range cfg.Models {
+		if err := m.Validate(); err != nil {
+			return Config{}, fmt.Errorf("error on model %d (%s): %w", i, m.Type, err)
+		}
+	}
 	return cfg, nil
 }
@@ -0,0 +1,41 @@
+package clock
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Step is the game time elapsed on each tick, timers and animations of the game move on with the ticks
+const Step = 30 * time.Millisecond
+
+// TickMsg is given to the current model on each step of the clock
+type TickMsg struct{}
+
+// Clock is the game time. It moves on with ticks instead of wall time,
+// so a run played back gets the same ticks between the same inputs.
+type Clock struct {
+	now   time.Duration
+	speed float64 // Wall time of a step is divided by speed
+}
+
+func (c Clock) GetTime() time.Duration { return c.now }
+func (c Clock) GetSpeed() float64      { return c.speed }
+
+// GetDelay return the wall time between two ticks
+func (c Clock) GetDelay() time.Duration { return time.Duration(float64(Step) / c.speed) }
+
+// Advance move the clock on by one step
+func (c *Clock) Advance() { c.now += Step }
+
+// Tick return the command sending the next tick
+func (c Clock) Tick() tea.Cmd {
+	return tea.Tick(c.GetDelay(), func(time.Time) tea.Msg {
+		return TickMsg{}
+	})
+}
+
+// New return a clock at time 0, running speed times faster than wall time
+func New(speed float64) Clock {
+	return Clock{speed: speed}
+}
@@ -0,0 +1,105 @@
+package clock
+
+import (
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var (
+	lastID int
+	idMtx  sync.Mutex
+)
+
+func nextID() int {
+	idMtx.Lock()
+	defer idMtx.Unlock()
+	lastID++
+	return lastID
+}
+
+// StartStopMsg start or stop the timer with the same ID
+type StartStopMsg struct {
+	ID      int
+	running bool
+}
+
+// TimeoutMsg is sent once when the timer is ended by a tick
+type TimeoutMsg struct {
+	ID int
+}
+
+// Timer is a countdown moving on with the clock ticks, it is stopped until started.
+// Like the bubbles timer, setting Timeout by hand does not send TimeoutMsg.
+type Timer struct {
+	Timeout  time.Duration // Time left
+	Interval time.Duration // Time removed from Timeout on each interval
+
+	id      int
+	running bool
+	elapsed time.Duration // Clock time since the last interval
+}
+
+func (t Timer) ID() int { return t.id }
+
+// Timedout return true if there is no time left
+func (t Timer) Timedout() bool { return t.Timeout <= 0 }
+
+// Running return true if the timer is started and not ended
+func (t Timer) Running() bool { return t.running && !t.Timedout() }
+
+// Init start the timer
+func (t Timer) Init() tea.Cmd { return t.Start() }
+
+func (t Timer) Update(msg tea.Msg) (Timer, tea.Cmd) {
+	switch msg := msg.(type) {
+	case StartStopMsg:
+		if msg.ID == t.id {
+			t.running = msg.running
+		}
+	case TickMsg:
+		if !t.Running() {
+			break
+		}
+		t.elapsed += Step
+		if t.elapsed < t.Interval {
+			break
+		}
+		t.elapsed -= t.Interval
+		t.Timeout -= t.Interval
+		if t.Timedout() {
+			return t, t.timedout()
+		}
+	}
+	return t, nil
+}
+
+func (t Timer) View() string { return t.Timeout.String() }
+
+// Start resume the timer
+func (t Timer) Start() tea.Cmd { return t.startStop(true) }
+
+// Stop pause the timer
+func (t Timer) Stop() tea.Cmd { return t.startStop(false) }
+
+func (t Timer) startStop(running bool) tea.Cmd {
+	return func() tea.Msg {
+		return StartStopMsg{ID: t.id, running: running}
+	}
+}
+
+func (t Timer) timedout() tea.Cmd {
+	return func() tea.Msg {
+		return TimeoutMsg{ID: t.id}
+	}
+}
+
+// NewTimer return a stopped timer of timeout, counting down by interval
+func NewTimer(timeout, interval time.Duration) Timer {
+	return Timer{
+		Timeout:  timeout,
+		Interval: interval,
+		id:       nextID(),
+	}
+}
@@ -1,15 +1,20 @@
 package game
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/franciscolkdo/breach-protocol/game/clock"
+	"github.com/franciscolkdo/breach-protocol/game/highscore"
 	"github.com/franciscolkdo/breach-protocol/game/keymap"
 	"github.com/franciscolkdo/breach-protocol/game/message"
 	"github.com/franciscolkdo/breach-protocol/game/model"
+	"github.com/franciscolkdo/breach-protocol/game/model/breach"
 	"github.com/franciscolkdo/breach-protocol/game/model/end"
 	"github.com/franciscolkdo/breach-protocol/game/style"
 	"github.com/franciscolkdo/breach-protocol/tools"
@@ -27,10 +32,41 @@ const (
 const AppName = "Breach Protocol"
 const footerName = "Bartmoss Team"
 
+// Result is the outcome of a run
+type Result struct {
+	Score     int
+	Level     int  // Number of models done
+	Completed bool // All models of the run are done
+}
+
+// Checkpoint is the model where the campaign restarts, with the score when it was reached
+type Checkpoint struct {
+	Index     int           `json:"index"`
+	Score     int           `json:"score"`
+	Remaining time.Duration `json:"remaining"`
+	Buffer    int           `json:"buffer"`
+}
+
 type Model struct {
-	models     []model.Config
+	levels     model.Levels
 	currentIdx int
+	seed       int64
+	score      int
+	remaining  time.Duration // Time left on timers of done breaches
+	buffer     int           // Buffer slots used in done breaches
+	levelScore int           // Score before the current model, restored on retry
+	checkpoint Checkpoint
+	lives      int  // Lives left, game over on a failure without lives if disabled
+	maxLives   int  // Lives on campaign start, 0 if disabled
+	noRetry    bool // Run can not be retried nor restarted from the end screen
 	current    tea.Model
+	currentId  int               // Id of the current model, end messages of previous models are ignored
+	result     *Result           // First ended run, restarted runs are not kept
+	save       *Save             // Campaign progress, not saved if nil
+	records    *highscore.Record // Campaign of high scores, not recorded if nil
+	clock      clock.Clock
+	record     *Replay // Recorded inputs, not recorded if nil
+	playback   *Replay // Inputs left to play back, nil if inputs come from the player
 
 	keyMap   keymap.KeyMap
 	ready    bool
@@ -40,15 +76,24 @@ type Model struct {
 
 // Init initializes the BreachModel.
 func (m Model) Init() tea.Cmd {
-	return tea.Sequence(tea.SetWindowTitle(AppName), m.current.Init())
+	return tea.Sequence(tea.SetWindowTitle(AppName), m.current.Init(), tea.Batch(m.clock.Tick(), m.nextPlay()))
 }
 
 func (m *Model) LoadModel() tea.Cmd {
-	var err error
-	if m.currentIdx > len(m.models)-1 {
-		m.current = end.NewModel(end.Config{Msg: "Félicitations tu as réussi!"})
+	m.currentId++
+	if !m.endless() && m.currentIdx > m.levels.Len()-1 {
+		m.endRun(true)
+		m.current = end.NewModel(end.Config{Msg: "Félicitations tu as réussi!", Seed: m.seed, Score: m.score, Scores: m.recordRun(), NoRestart: m.noRetry})
 	} else {
-		m.current, err = m.models[m.currentIdx].Load()
+		cfg, err := m.levels.Get(m.currentIdx)
+		if err != nil {
+			return tea.Quit
+		}
+		m.levelScore = m.score
+		if cfg.Checkpoint {
+			m.checkpoint = Checkpoint{Index: m.currentIdx, Score: m.score, Remaining: m.remaining, Buffer: m.buffer}
+		}
+		m.current, err = cfg.Load(model.State{Id: m.currentId, Seed: m.seed, Level: m.currentIdx, Score: m.score})
 		if err != nil {
 			return tea.Quit
 		}
@@ -56,6 +101,56 @@ func (m *Model) LoadModel() tea.Cmd {
 	return m.current.Init()
 }
 
+// endRun keep the result of the first ended run
+func (m *Model) endRun(completed bool) {
+	if m.result == nil {
+		m.result = &Result{Score: m.score, Level: m.currentIdx, Completed: completed}
+	}
+}
+
+// GetResult return the result of the first ended run, or the current progress if the run is not ended
+func (m Model) GetResult() Result {
+	if m.result != nil {
+		return *m.result
+	}
+	return Result{Score: m.score, Level: m.currentIdx}
+}
+
+// WithLives return the game with a number of lives, a failed model is retried until there is no life left
+func (m Model) WithLives(lives int) Model {
+	m.lives, m.maxLives = lives, lives
+	return m
+}
+
+// WithoutRetry return the game played only once, without retry nor restart, like a daily challenge
+func (m Model) WithoutRetry() Model {
+	m.noRetry = true
+	return m
+}
+
+// restart go back to the last checkpoint with all lives
+func (m *Model) restart() {
+	m.currentIdx = m.checkpoint.Index
+	m.score = m.checkpoint.Score
+	m.remaining, m.buffer = m.checkpoint.Remaining, m.checkpoint.Buffer
+	m.lives = m.maxLives
+}
+
+// endless return true if the run never ends, until the player fails
+func (m Model) endless() bool { return m.levels.Len() < 0 }
+
+// endConfig return the end screen config of a failed run
+func (m Model) endConfig(msg string) end.Config {
+	// Only campaigns can be retried, with lives it would give a new life
+	cfg := end.Config{Msg: msg, Seed: m.seed, Score: m.score, Retry: !m.endless() && !m.noRetry && m.maxLives == 0, NoRestart: m.noRetry}
+	// Endless run ends on the first failure
+	if m.endless() {
+		cfg.Level = m.currentIdx + 1
+		cfg.Scores = m.recordRun()
+	}
+	return cfg
+}
+
 // Update handle messages for BreachModel.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds := []tea.Cmd{}
@@ -79,31 +174,74 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.QuitMsg:
 		m.askQuit = true
 		cmds = append(cmds, tea.Quit)
-	// Handle key strokes and send them to current model
+	// Handle key strokes and send them to current model, only quit is handled on replay
 	case tea.KeyMsg:
 		if key.Matches(msg, m.keyMap.Quit) {
 			cmds = append(cmds, tea.Quit)
-		} else {
-			m.current, cmd = m.current.Update(msg)
-			cmds = append(cmds, cmd)
-			m.viewport, cmd = m.viewport.Update(msg)
+		} else if m.playback == nil {
+			m.recordInput(msg)
+			cmds = append(cmds, m.onKey(msg))
+		}
+	// Mouse is given to the current model relative to its view, viewport handles the wheel
+	case tea.MouseMsg:
+		m.viewport, cmd = m.viewport.Update(msg)
+		cmds = append(cmds, cmd)
+		if m.playback == nil {
+			local := m.localMouse(msg)
+			m.recordInput(local)
+			m.current, cmd = m.current.Update(local)
 			cmds = append(cmds, cmd)
 		}
-	// EndModelMsg return the state of current model, show end game if failed or next one on success
+	// Clock tick is given to the current model, the clock stops at the end of the replay
+	case clock.TickMsg:
+		if !m.replayEnded() {
+			m.clock.Advance()
+			m.current, cmd = m.current.Update(msg)
+			cmds = append(cmds, cmd, m.clock.Tick(), m.nextPlay())
+		}
+	case playMsg:
+		cmds = append(cmds, m.playInputs())
+	// EndModelMsg return the state of current model, show end game if failed or next one on success.
+	// A failed model is retried while lives are left, end messages of previous models are ignored.
 	case message.EndModelMsg:
-		if msg.Status == message.Failed {
-			m.current = end.NewModel(end.Config{Msg: msg.Msg})
+		if msg.Id != m.currentId {
+			break
+		}
+		m.score += msg.Points
+		if msg.Status == message.Failed && m.maxLives > 0 {
+			m.lives--
+		}
+		if msg.Status == message.Failed && m.lives > 0 {
+			m.score = m.levelScore
+			m.writeSave()
+			cmds = append(cmds, m.LoadModel())
+		} else if msg.Status == message.Failed {
+			m.endRun(false)
+			m.writeSave()
+			m.current = end.NewModel(m.endConfig(msg.Msg))
 			cmds = append(cmds, m.current.Init())
 		} else {
+			if _, ok := m.current.(breach.Model); ok {
+				m.remaining += msg.Remaining
+				m.buffer += msg.Buffer
+				m.recordLevel(msg)
+			}
 			m.currentIdx++
+			m.writeSave()
 			cmds = append(cmds, m.LoadModel())
 		}
-	// EndGame return Restart or Quit, set currentIdx=0 on restart
+	// EndGame return Retry, Restart or Quit, retry the failed model or restart from the last checkpoint
 	case end.EndGameMsg:
-		if msg == end.Quit {
+		switch msg {
+		case end.Quit:
 			return m, tea.Quit
-		} else {
-			m.currentIdx = 0
+		case end.Retry:
+			m.score = m.levelScore
+			m.writeSave()
+			cmds = append(cmds, m.LoadModel())
+		default:
+			m.restart()
+			m.writeSave()
 			cmds = append(cmds, m.LoadModel())
 		}
 	// Pass all messages not already handled (internal msg for current model)
@@ -122,7 +260,18 @@ func (m Model) View() string {
 	var s strings.Builder
 	// Set Header
 	tools.NewLine(&s)
-	s.WriteString(m.titleView(AppName))
+	header := []string{AppName}
+	if m.endless() {
+		header = append(header, fmt.Sprintf("Level: %d", m.currentIdx+1))
+	}
+	if m.maxLives > 0 {
+		header = append(header, fmt.Sprintf("Lives: %d/%d", m.lives, m.maxLives))
+	}
+	header = append(header, fmt.Sprintf("Score: %d", m.score))
+	if m.playback != nil {
+		header = append(header, m.replayView())
+	}
+	s.WriteString(m.titleView(strings.Join(header, " - ")))
 
 	// Set current Model view
 	tools.NewLine(&s)
@@ -135,6 +284,21 @@ func (m Model) View() string {
 	return style.RootStyle.Render(s.String())
 }
 
+// onKey give the key to the current model and the viewport
+func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
+	var cmd1, cmd2 tea.Cmd
+	m.current, cmd1 = m.current.Update(msg)
+	m.viewport, cmd2 = m.viewport.Update(msg)
+	return tea.Batch(cmd1, cmd2)
+}
+
+// localMouse return the mouse message with a position relative to the current model view, centered in the viewport
+func (m Model) localMouse(msg tea.MouseMsg) tea.MouseMsg {
+	msg.X -= style.PlaceOffset(m.viewport.Width-lipgloss.Width(m.current.View()), lipgloss.Center)
+	msg.Y -= m.viewport.YPosition - m.viewport.YOffset
+	return msg
+}
+
 func (m Model) center(content string) string {
 	return lipgloss.Place(m.viewport.Width, lipgloss.Height(content), lipgloss.Center, lipgloss.Center, content, lipgloss.WithWhitespaceBackground(style.DarkGray))
 }
@@ -160,14 +324,16 @@ var gameStyle = GameStyle{
 	Title: style.RootStyle.Foreground(style.MetallicGold),
 }
 
-// NewGame return a game model instance
-func NewGame(models []model.Config) Model {
+// NewGame return a game model instance playing the levels, all random generation is driven by the seed
+func NewGame(levels model.Levels, seed int64) Model {
 	g := Model{
-		models:     models,
+		levels:     levels,
+		seed:       seed,
 		ready:      false,
 		askQuit:    false,
 		currentIdx: 0,
 		keyMap:     keymap.DefaultKeyMap(),
+		clock:      clock.New(1),
 	}
 	_ = g.LoadModel()
 	return g
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/franciscolkdo/breach-protocol/game/model"
+)
+
+var _ model.Levels = Arcade{}
+
+// arcadeCodes is the pool of symbols of arcade breaches, the alphabet grows along the run
+var arcadeCodes = []string{"55", "BD", "E9", "1C", "7A", "FF", "C3", "0E", "9B"}
+
+// Arcade is an endless run of generated breaches, getting harder on each level
+type Arcade struct {
+	Seed int64
+}
+
+// Len return a negative number, arcade never ends
+func (a Arcade) Len() int { return -1 }
+
+// Get return the generated breach of the level, the same seed always gives the same level
+func (a Arcade) Get(idx int) (model.Config, error) {
+	rng := rand.New(rand.NewSource(model.LevelSeed(a.Seed, idx)))
+	lvl, err := arcadeSettings(idx).level(rng)
+	if err != nil {
+		return model.Config{}, fmt.Errorf("error on arcade level %d: %w", idx+1, err)
+	}
+	return model.NewConfig("breach", lvl)
+}
+
+// arcadeSettings return the settings of the level, the matrix and alphabet grow while buffer slack and timer shrink
+func arcadeSettings(idx int) settings {
+	s := settings{
+		Matrix:    min(5+idx/3, 9),
+		Sequences: min(1+idx/2, 4),
+		Size:      min(2+idx/3, 5),
+		Timer:     max(20, 60-3*idx),
+		Alphabet:  arcadeCodes[:min(4+idx/4, len(arcadeCodes))],
+	}
+	// Buffer fits the longest sequence, one symbol for each other sequence and some slack
+	slack := max(1, 4-idx/3)
+	s.Buffer = s.Size + s.Sequences - 1 + slack
+	return s
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,37 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/franciscolkdo/breach-protocol/game/model"
+)
+
+// dailyLevels is the number of breaches of a daily challenge
+const dailyLevels = 3
+
+// DailySeed return the seed of the daily challenge, the same for everyone on the UTC date
+func DailySeed(date time.Time) int64 {
+	y, m, d := date.UTC().Date()
+	return int64(y*10000 + int(m)*100 + d)
+}
+
+// Daily return the breaches of the daily challenge of the date
+func Daily(date time.Time) (model.Campaign, error) {
+	rng := rand.New(rand.NewSource(DailySeed(date)))
+	p := presets[Normal]
+	var res model.Campaign
+	for i := 0; i < dailyLevels; i++ {
+		lvl, err := p.at(i, dailyLevels).level(rng)
+		if err != nil {
+			return nil, fmt.Errorf("error on daily level %d: %w", i+1, err)
+		}
+		cfg, err := model.NewConfig("breach", lvl)
+		if err != nil {
+			return nil, fmt.Errorf("error on daily level %d: %w", i+1, err)
+		}
+		res = append(res, cfg)
+	}
+	return res, nil
+}
@@ -0,0 +1,205 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/franciscolkdo/breach-protocol/config"
+	"github.com/franciscolkdo/breach-protocol/game/model"
+	"github.com/franciscolkdo/breach-protocol/game/model/breach"
+	"github.com/franciscolkdo/breach-protocol/game/model/story"
+	"github.com/franciscolkdo/breach-protocol/game/solver"
+)
+
+// maxAttempts is the number of seeds tried for a level before giving up
+const maxAttempts = 100
+
+// Difficulty is a preset of the difficulty curve of a campaign
+type Difficulty string
+
+const (
+	Easy   Difficulty = "easy"
+	Normal Difficulty = "normal"
+	Hard   Difficulty = "hard"
+	Insane Difficulty = "insane"
+)
+
+// Difficulties is the list of available presets
+var Difficulties = []Difficulty{Easy, Normal, Hard, Insane}
+
+// span is a setting going from First on the first level to Last on the last level
+type span struct {
+	First int
+	Last  int
+}
+
+// at return the value of the setting for the level among count levels
+func (s span) at(level, count int) int {
+	if count <= 1 {
+		return s.First
+	}
+	return s.First + (s.Last-s.First)*level/(count-1)
+}
+
+// preset set the curve of each breach setting along the campaign
+type preset struct {
+	Matrix    span
+	Buffer    span
+	Timer     span // Seconds of the breach timer
+	Sequences span // Number of sequences to upload
+	Size      span // Size of the longest sequence
+	Undo      bool
+	NoPreview bool
+}
+
+// at return the settings of the level among count levels
+func (p preset) at(level, count int) settings {
+	return settings{
+		Matrix:    p.Matrix.at(level, count),
+		Buffer:    p.Buffer.at(level, count),
+		Timer:     p.Timer.at(level, count),
+		Sequences: p.Sequences.at(level, count),
+		Size:      p.Size.at(level, count),
+		Undo:      p.Undo,
+		NoPreview: p.NoPreview,
+	}
+}
+
+var presets = map[Difficulty]preset{
+	Easy:   {Matrix: span{5, 6}, Buffer: span{8, 8}, Timer: span{60, 45}, Sequences: span{1, 2}, Size: span{2, 3}, Undo: true},
+	Normal: {Matrix: span{5, 7}, Buffer: span{8, 7}, Timer: span{45, 30}, Sequences: span{1, 3}, Size: span{3, 4}, Undo: true},
+	Hard:   {Matrix: span{6, 8}, Buffer: span{8, 7}, Timer: span{35, 20}, Sequences: span{2, 3}, Size: span{3, 5}, NoPreview: true},
+	Insane: {Matrix: span{7, 8}, Buffer: span{7, 6}, Timer: span{25, 15}, Sequences: span{3, 4}, Size: span{4, 5}, NoPreview: true},
+}
+
+// settings describe a breach level to generate
+type settings struct {
+	Matrix    int
+	Buffer    int
+	Timer     int
+	Sequences int
+	Size      int
+	Undo      bool
+	NoPreview bool
+	Alphabet  []string // Symbol codes of the breach, default alphabet if not set
+}
+
+// Options describe the campaign to generate
+type Options struct {
+	Levels     int
+	Difficulty Difficulty
+	Seed       int64
+}
+
+// level is the breach config written in the campaign file, only set fields are written
+type level struct {
+	Matrix    int        `json:"matrix"`
+	Buffer    int        `json:"buffer"`
+	Timer     int        `json:"timer"`
+	Undo      bool       `json:"undo,omitempty"`
+	NoPreview bool       `json:"noPreview,omitempty"`
+	Alphabet  []string   `json:"alphabet,omitempty"`
+	Seed      int64      `json:"seed"`
+	Sequences []sequence `json:"sequences"`
+}
+
+type sequence struct {
+	Description string `json:"description"`
+	Size        int    `json:"size"`
+	Points      int    `json:"points"`
+}
+
+// Generate return a campaign of breach levels, each one preceded by a story placeholder.
+// Each breach is checked with the solver, its seed is kept in the config so the level is the same on each run.
+func Generate(opts Options) (config.Config, error) {
+	p, ok := presets[opts.Difficulty]
+	if !ok {
+		return config.Config{}, fmt.Errorf("unknown difficulty %q, expected one of %v", opts.Difficulty, Difficulties)
+	}
+	if opts.Levels <= 0 {
+		return config.Config{}, fmt.Errorf("number of levels should be positive, got %d", opts.Levels)
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	var cfg config.Config
+	for i := 0; i < opts.Levels; i++ {
+		intro, err := model.NewConfig("story", story.Config{
+			Type: story.Text,
+			Text: fmt.Sprintf("Level %d/%d: write the story of this breach here.", i+1, opts.Levels),
+		})
+		if err != nil {
+			return config.Config{}, err
+		}
+		lvl, err := p.at(i, opts.Levels).level(rng)
+		if err != nil {
+			return config.Config{}, fmt.Errorf("error on level %d: %w", i+1, err)
+		}
+		level, err := model.NewConfig("breach", lvl)
+		if err != nil {
+			return config.Config{}, fmt.Errorf("error on level %d: %w", i+1, err)
+		}
+		cfg.Models = append(cfg.Models, intro, level)
+	}
+	return cfg, nil
+}
+
+// level return a winnable breach level, trying new seeds until the solver uploads all sequences
+func (s settings) level(rng *rand.Rand) (level, error) {
+	lvl := level{
+		Matrix:    s.Matrix,
+		Buffer:    s.Buffer,
+		Timer:     s.Timer,
+		Undo:      s.Undo,
+		NoPreview: s.NoPreview,
+		Alphabet:  s.Alphabet,
+	}
+	for i := 0; i < s.Sequences; i++ {
+		// Sequences get shorter from the longest one, with at least 2 symbols
+		size := max(2, s.Size-i)
+		lvl.Sequences = append(lvl.Sequences, sequence{
+			Description: fmt.Sprintf("Sequence %d", i+1),
+			Size:        size,
+			Points:      size * 10,
+		})
+	}
+	for i := 0; i < maxAttempts; i++ {
+		// Seed 0 is replaced by the run seed in game, the level would change on each run
+		lvl.Seed = rng.Int63n(math.MaxInt64) + 1
+		cfg := lvl.config()
+		if err := cfg.Validate(); err != nil {
+			return level{}, err
+		}
+		matrix, sequences := cfg.Generate(rand.New(rand.NewSource(cfg.Seed)))
+		if solver.Solve(matrix, cfg.Buffer, breach.X, sequences).Complete {
+			return lvl, nil
+		}
+	}
+	return level{}, fmt.Errorf("no winnable breach found after %d attempts", maxAttempts)
+}
+
+// config return the breach config loaded by the game for the level
+func (l level) config() breach.Config {
+	cfg := breach.Config{
+		Buffer:    l.Buffer,
+		Matrix:    breach.Square(l.Matrix),
+		Timer:     time.Duration(l.Timer),
+		Undo:      l.Undo,
+		NoPreview: l.NoPreview,
+		Seed:      l.Seed,
+	}
+	for _, code := range l.Alphabet {
+		cfg.Alphabet = append(cfg.Alphabet, breach.SymbolWeight{Code: breach.Symbol(code), Weight: 1})
+	}
+	for _, s := range l.Sequences {
+		cfg.Sequences = append(cfg.Sequences, breach.SequenceConfig{Description: s.Description, Size: s.Size, Points: s.Points})
+	}
+	return cfg
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,69 @@
+package highscore
+
+import (
+	"sort"
+	"time"
+
+	"github.com/franciscolkdo/breach-protocol/storage"
+)
+
+// scoresFile is the file of the data directory keeping high scores
+const scoresFile = "scores.json"
+
+// Campaign is the level of a record for a whole campaign run
+const Campaign = -1
+
+// Top is the number of records shown in tables
+const Top = 10
+
+// Record is the result of a campaign run or of a breach level
+type Record struct {
+	Campaign  string        `json:"campaign"`  // Hash of the campaign
+	Name      string        `json:"name"`      // Name of the campaign shown in tables
+	Level     int           `json:"level"`     // Index of the breach model in the campaign, Campaign for the whole run
+	Score     int           `json:"score"`     // Points earned
+	Remaining time.Duration `json:"remaining"` // Time left on breach timers
+	Buffer    int           `json:"buffer"`    // Buffer slots used
+	Date      time.Time     `json:"date"`
+}
+
+// Read return all the records
+func Read() ([]Record, error) {
+	var res []Record
+	if err := storage.Read(scoresFile, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Add save the new records with the existing ones
+func Add(records ...Record) error {
+	all, err := Read()
+	if err != nil {
+		return err
+	}
+	return storage.Write(scoresFile, append(all, records...))
+}
+
+// Filter return the records matching the campaign and level, sorted from the best one.
+// An empty campaign matches all campaigns, by hash or name.
+// Best records have the highest score, then the most remaining time, then the fewest buffer slots used.
+func Filter(records []Record, campaign string, level int) []Record {
+	var res []Record
+	for _, r := range records {
+		if r.Level == level && (campaign == "" || r.Campaign == campaign || r.Name == campaign) {
+			res = append(res, r)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		switch {
+		case res[i].Score != res[j].Score:
+			return res[i].Score > res[j].Score
+		case res[i].Remaining != res[j].Remaining:
+			return res[i].Remaining > res[j].Remaining
+		default:
+			return res[i].Buffer < res[j].Buffer
+		}
+	})
+	return res
+}
@@ -9,6 +9,7 @@ type KeyMap struct {
 	Left   key.Binding
 	Right  key.Binding
 	Select key.Binding
+	Undo   key.Binding
 	Quit   key.Binding
 }
 
@@ -20,6 +21,7 @@ func DefaultKeyMap() KeyMap {
 		Left:   key.NewBinding(key.WithKeys("backspace", "left", "esc"), key.WithHelp("h", "left")),
 		Right:  key.NewBinding(key.WithKeys("l", "right"), key.WithHelp("l", "right")),
 		Select: key.NewBinding(key.WithKeys("enter"), key.WithHelp("a", "select")),
+		Undo:   key.NewBinding(key.WithKeys("u", "ctrl+z"), key.WithHelp("u", "undo")),
 		Quit:   key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
 	}
 }
@@ -1,6 +1,10 @@
 package message
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
 
 type EndViewStatus int
 
@@ -14,6 +18,10 @@ type EndModelMsg struct {
 	Id     int           // Id of sender view
 	Status EndViewStatus // End status
 	Msg    string        // additional data from sender
+	Points int           // Points earned in sender view
+
+	Remaining time.Duration // Time left on the sender timer
+	Buffer    int           // Buffer slots used in sender view
 }
 
 func OnEndViewMsg(msg EndModelMsg) tea.Cmd {
@@ -2,11 +2,13 @@ package breach
 
 import (
 	"fmt"
+	"math/rand"
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/bubbles/timer"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/franciscolkdo/breach-protocol/game/clock"
 	"github.com/franciscolkdo/breach-protocol/game/keymap"
 	"github.com/franciscolkdo/breach-protocol/game/message"
 	"github.com/franciscolkdo/breach-protocol/game/style"
@@ -34,15 +36,37 @@ const AppName = "Breach Protocol"
 const (
 	SequencesDone = "All sequences are completed"
 	TimerDone     = "Timer is ended"
+	TimerExpired  = "Sequence timer is ended"
 )
 
+// pick is the state saved on each selection, to be able to undo it
+type pick struct {
+	pos       Coordinate
+	symbol    Symbol
+	buffer    int        // Buffer position before the selection
+	sequences []Sequence // Sequences before the selection
+}
+
 // Model is the mini game breach-protocol model
 type Model struct {
 	id        int
+	ended     bool // End message is sent, the breach ends only once
 	matrix    MatrixModel
 	buffer    Buffer
 	sequences []Sequence
-	timer     timer.Model
+	timer     clock.Timer
+	started   bool // Timer is started
+	undo      bool
+	undoCost  time.Duration
+	history   []pick
+	rng       *rand.Rand
+	effects   map[int]bool    // Sequences with an effect already applied
+	freezes   []time.Duration // Time left of each running timer freeze
+	alphabet  Alphabet
+	preview   bool // Show the effect of the hovered symbol on sequences
+
+	firewall         clock.Timer
+	firewallInterval time.Duration
 
 	Width  int
 	Height int
@@ -57,32 +81,115 @@ func (m *Model) SetSize(msg tea.WindowSizeMsg) {
 
 // checkBufferSize read the buffer free size. The game is over when size <= 0
 // It raise a buffer too small if sequence size is higher than buffer size.
-func (m Model) checkBufferSize(size BufferSizeMsg) (tea.Model, tea.Cmd) {
+// The size is read from the buffer as it may have been extended since the message.
+func (m Model) checkBufferSize() (tea.Model, tea.Cmd) {
+	size := m.buffer.GetEmptySize()
 	if size <= 0 {
 		return m.isOver(BufferIsFull)
 	}
 	cmds := []tea.Cmd{}
 	for _, seq := range m.sequences {
-		if !seq.IsDone() && seq.Last() > int(size) {
+		if !seq.IsDone() && seq.Last() > size {
 			cmds = append(cmds, OnBufferTooSmallMsg(seq.Id))
 		}
 	}
 	return m, tea.Batch(cmds...)
 }
 
-// isOver verify if the game is over or not. To continue, the player should have fullfilled at least one sequence in the round
+// isOver verify if the game is over or not. To continue, the player should have fullfilled at least one sequence in the round.
+// The end message is sent only once, even if several end conditions are met by the same pick.
 func (m Model) isOver(reason string) (tea.Model, tea.Cmd) {
+	if m.ended {
+		return m, nil
+	}
+	m.ended = true
 	status := message.Failed
 	if idx := slices.IndexFunc(m.sequences, func(seq Sequence) bool { return seq.GetStatus() == SequenceSuccess }); idx >= 0 {
 		status = message.Success
 	}
-	return m, message.OnEndViewMsg(message.EndModelMsg{Id: m.id, Status: status, Msg: reason})
+	remaining := m.timer.Timeout
+	if remaining < 0 {
+		remaining = 0
+	}
+	return m, message.OnEndViewMsg(message.EndModelMsg{
+		Id:        m.id,
+		Status:    status,
+		Msg:       reason,
+		Points:    m.points(),
+		Remaining: remaining,
+		Buffer:    m.buffer.x,
+	})
+}
+
+// points return the sum of points of uploaded sequences
+func (m Model) points() int {
+	var res int
+	for _, seq := range m.sequences {
+		if seq.GetStatus() == SequenceSuccess {
+			res += seq.GetPoints()
+		}
+	}
+	return res
+}
+
+// undoPick restore the matrix, buffer and sequences as before the last selection, it cost undoCost on timer
+func (m Model) undoPick() (tea.Model, tea.Cmd) {
+	if len(m.history) == 0 {
+		return m, nil
+	}
+	last := m.history[len(m.history)-1]
+	m.history = m.history[:len(m.history)-1]
+	current := m.sequences
+	m.matrix.restore(last.pos, last.symbol)
+	m.buffer.Rewind(last.buffer)
+	m.buffer.SetCurrentSymbol(last.symbol)
+	m.sequences = last.sequences
+	var cmds []tea.Cmd
+	for i := range m.sequences {
+		cmds = append(cmds, m.sequences[i].keepTimer(current[i]))
+	}
+	if m.started {
+		m.timer.Timeout -= m.undoCost
+		// Timer does not send timeout message when stopped by the cost
+		if m.timer.Timedout() {
+			return m.isOver(TimerDone)
+		}
+	}
+	return m, tea.Batch(cmds...)
 }
 
 // Init initializes the BreachModel.
 func (m Model) Init() tea.Cmd {
 	m.buffer.SetCurrentSymbol(m.matrix.GetSymbol())
-	return m.timer.Init()
+	m.previewSymbol()
+	if !m.started {
+		return nil
+	}
+	if m.firewallEnabled() {
+		return tea.Batch(m.timer.Init(), m.firewall.Init(), m.startSequenceTimers())
+	}
+	return tea.Batch(m.timer.Init(), m.startSequenceTimers())
+}
+
+// previewSymbol show the effect of the symbol under the cursor on sequences, if enabled
+func (m Model) previewSymbol() {
+	if !m.preview {
+		return
+	}
+	for i := range m.sequences {
+		m.sequences[i].preview(m.matrix.GetSymbol())
+	}
+}
+
+// startSequenceTimers start the expiry timers of running sequences
+func (m Model) startSequenceTimers() tea.Cmd {
+	var cmds []tea.Cmd
+	for i := range m.sequences {
+		if m.sequences[i].GetStatus() == SequenceRunning {
+			cmds = append(cmds, m.sequences[i].StartTimer())
+		}
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update handle messages for BreachModel.
@@ -91,46 +198,122 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Resize window
 	case tea.WindowSizeMsg:
 		m.SetSize(msg)
-	// Handle timer tick update
-	case timer.TickMsg, timer.StartStopMsg:
-		var cmd tea.Cmd
-		m.timer, cmd = m.timer.Update(msg)
-		return m, cmd
-	// End round on timer timeout
-	case timer.TimeoutMsg:
-		return m.isOver(TimerDone)
+	// Move timers and freezes on with the clock
+	case clock.TickMsg, clock.StartStopMsg:
+		cmds := make([]tea.Cmd, 2, 2+len(m.sequences))
+		// Timer does not count down while frozen
+		if _, ok := msg.(clock.TickMsg); !ok || len(m.freezes) == 0 {
+			m.timer, cmds[0] = m.timer.Update(msg)
+		}
+		m.firewall, cmds[1] = m.firewall.Update(msg)
+		if _, ok := msg.(clock.TickMsg); ok {
+			m.thaw()
+		}
+		for i, seq := range m.sequences {
+			var cmd tea.Cmd
+			m.sequences[i], cmd = seq.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+	// Reshuffle matrix on firewall timeout, end round on timer timeout, other timers are sequences ones
+	case clock.TimeoutMsg:
+		switch msg.ID {
+		case m.firewall.ID():
+			return m.reshuffle()
+		case m.timer.ID():
+			return m.isOver(TimerDone)
+		}
+		var cmds []tea.Cmd
+		for i, seq := range m.sequences {
+			var cmd tea.Cmd
+			m.sequences[i], cmd = seq.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
 	// Check buffer size on new symbol saved to see if the game is over
 	case BufferSizeMsg:
-		return m.checkBufferSize(msg)
+		return m.checkBufferSize()
 	case tea.KeyMsg:
+		if m.undo && key.Matches(msg, m.keyMap.Undo) {
+			return m.undoPick()
+		}
+		var cmd tea.Cmd
+		m.matrix, cmd = m.matrix.Update(msg)
+		return m, cmd
+	// Mouse position is given in the breach view, matrix needs it in its own view
+	case tea.MouseMsg:
 		var cmd tea.Cmd
+		msg.Y -= m.matrixTop()
 		m.matrix, cmd = m.matrix.Update(msg)
 		return m, cmd
 	// Check sequences status on symbols saved
 	case SequenceStatusMsg:
 		if msg.Status == SequenceSuccess || msg.Status == SequenceFailed {
+			// Start sequences waiting for this one, they can not be uploaded anymore if it failed
+			var cmds []tea.Cmd
+			for i, seq := range m.sequences {
+				if seq.GetParent() != msg.Id || !seq.IsWaiting() {
+					continue
+				}
+				if msg.Status == SequenceSuccess {
+					m.sequences[i].status = SequenceRunning
+					cmds = append(cmds, m.sequences[i].StartTimer())
+				} else {
+					m.sequences[i].status = SequenceFailed
+					cmds = append(cmds, OnSequenceStatusMsg(seq.Id, SequenceFailed))
+				}
+			}
 			// Check if any sequence is not Done
 			if idx := slices.IndexFunc(m.sequences, func(seq Sequence) bool { return !seq.IsDone() }); idx < 0 {
 				reason := SequencesDone
 				// If all sequences are done, update end reason if all sequences are completed or not
-				if idx := slices.IndexFunc(m.sequences, func(seq Sequence) bool { return seq.GetStatus() == SequenceFailed }); idx >= 0 {
+				if idx := slices.IndexFunc(m.sequences, func(seq Sequence) bool { return seq.IsExpired() }); idx >= 0 {
+					reason = TimerExpired
+				} else if idx := slices.IndexFunc(m.sequences, func(seq Sequence) bool { return seq.GetStatus() == SequenceFailed }); idx >= 0 {
 					reason = NotEnoughSpace
 				}
 				return m.isOver(reason)
 			}
-			return m, nil
+			return m, tea.Batch(cmds...)
 		}
 	// Pass all messages not already handled to buffer and sequences
 	default:
 		var cmds []tea.Cmd
 		var cmd tea.Cmd
+		if msg, ok := msg.(SymbolMsg); ok && msg.selected {
+			// Save state before the selection to undo it
+			if m.undo {
+				m.history = append(m.history, pick{pos: msg.pos, symbol: msg.symbol, buffer: m.buffer.x, sequences: slices.Clone(m.sequences)})
+			}
+			// Start the timer on first selected symbol
+			if !m.started {
+				m.started = true
+				cmds = append(cmds, m.timer.Start(), m.startSequenceTimers())
+				if m.firewallEnabled() {
+					cmds = append(cmds, m.firewall.Start())
+				}
+			}
+		}
 		// Update buffer and sequences
 		m.buffer, cmd = m.buffer.Update(msg)
 		cmds = append(cmds, cmd)
 		for i, seq := range m.sequences {
+			// Hovered symbols are previewed only if enabled
+			if msg, ok := msg.(SymbolMsg); ok && !msg.selected && !m.preview {
+				continue
+			}
 			var cmd tea.Cmd
 			m.sequences[i], cmd = seq.Update(msg)
 			cmds = append(cmds, cmd)
+			// Apply effect as soon as the sequence is uploaded
+			if seq.GetStatus() == SequenceRunning && m.sequences[i].GetStatus() == SequenceSuccess {
+				m = m.applyEffect(m.sequences[i])
+			}
+		}
+		// Apply penalty of selected trap once the symbol is saved
+		if msg, ok := msg.(SymbolMsg); ok && msg.trap.Type != NoPenalty {
+			m, cmd = m.applyPenalty(msg.trap)
+			cmds = append(cmds, cmd)
 		}
 		return m, tea.Batch(cmds...)
 	}
@@ -153,6 +336,14 @@ func (m Model) View() string {
 	return style.RootStyle.Render(s.String())
 }
 
+// matrixTop return the first line of the matrix in the breach view.
+// Body starts on the last line of the timer, the matrix is centered in the body if sequences are higher.
+func (m Model) matrixTop() int {
+	matrix := lipgloss.Height(m.matrix.View())
+	body := max(matrix, lipgloss.Height(m.sequencesView()))
+	return lipgloss.Height(m.timerView()) - 1 + style.JoinOffset(body-matrix, lipgloss.Center)
+}
+
 // sequencesView return the sequences view
 func (m Model) sequencesView() string {
 	var s strings.Builder
@@ -169,21 +360,45 @@ func (m Model) sequencesView() string {
 func (m Model) timerView() string {
 	var s strings.Builder
 	time := style.RootStyle.Foreground(style.NeonMagenta).Render(fmt.Sprintf("%.4s", m.timer.View()))
-	s.WriteString(style.RootStyle.
+	if !m.started {
+		time = style.RootStyle.Foreground(style.NeonCyan).Render("Standing by (" + m.timer.View() + ")")
+	} else if len(m.freezes) > 0 {
+		time = style.RootStyle.Foreground(style.NeonCyan).Render("Frozen (" + m.timer.View() + ")")
+	}
+	box := style.RootStyle.
 		Border(lipgloss.NormalBorder()).BorderBackground(style.DarkGray).
 		Foreground(style.MetallicGold).
-		Padding(0, 1).Render("Breach Time Remaining: " + time))
+		Padding(0, 1)
+	s.WriteString(box.Render("Breach Time Remaining: " + time))
+	if m.firewallEnabled() {
+		firewall := style.RootStyle.Foreground(style.NeonPink).Render(fmt.Sprintf("%.4s", m.firewall.View()))
+		return lipgloss.JoinHorizontal(lipgloss.Top, s.String(), style.RootStyle.Render(" "), box.Render("Firewall Reshuffle: "+firewall))
+	}
 	return s.String()
 }
 
 // NewModel return a breach model instance
+// Sequences are derived from a legal path in the matrix, so each round can be won
 func NewModel(cfg Config) tea.Model {
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	matrix, sequences := cfg.Generate(rng)
 	return Model{
-		matrix:    NewMatrix(cfg.Matrix),
+		matrix:    matrix,
 		buffer:    NewBuffer(cfg.Buffer),
-		sequences: NewSequences(cfg.Sequences),
+		sequences: sequences,
+
+		timer:    clock.NewTimer(cfg.Timer*time.Second, time.Second),
+		started:  !cfg.StartOnSelect,
+		undo:     cfg.Undo,
+		undoCost: cfg.UndoCost * time.Second,
+		rng:      rng,
+		alphabet: cfg.GetAlphabet(),
+		effects:  make(map[int]bool),
+		keyMap:   keymap.DefaultKeyMap(),
+		id:       cfg.Id,
 
-		timer:  timer.NewWithInterval(cfg.Timer*time.Second, time.Second),
-		keyMap: keymap.DefaultKeyMap(),
+		firewall:         clock.NewTimer(cfg.Firewall*time.Second, time.Second),
+		firewallInterval: cfg.Firewall * time.Second,
+		preview:          !cfg.NoPreview,
 	}
 }
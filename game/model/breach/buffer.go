@@ -33,7 +33,9 @@ type Buffer struct {
 func (b Buffer) GetEmptySize() int { return len(b.data) - b.x }
 
 func (b *Buffer) SetCurrentSymbol(sym Symbol) {
-	b.data[b.x] = sym
+	if b.x < len(b.data) {
+		b.data[b.x] = sym
+	}
 }
 
 func (b Buffer) UseBufferBlock() (Buffer, tea.Cmd) {
@@ -45,6 +47,20 @@ func (b Buffer) UseBufferBlock() (Buffer, tea.Cmd) {
 	return b, OnBufferSizeMsg(b.GetEmptySize())
 }
 
+// Rewind free all blocks used after position x
+func (b *Buffer) Rewind(x int) {
+	for i := x; i < len(b.data); i++ {
+		b.data[i] = ""
+	}
+	b.x = x
+	b.isFull = false
+}
+
+// Extend add empty blocks at the end of the buffer
+func (b *Buffer) Extend(size int) {
+	b.data = append(b.data, make([]Symbol, size)...)
+}
+
 func (b Buffer) Init() tea.Cmd { return nil }
 
 func (b Buffer) Update(msg tea.Msg) (Buffer, tea.Cmd) {
@@ -71,6 +87,10 @@ func (b Buffer) View() string {
 		} else if i > b.x {
 			msg = "  "
 		}
+		// Current symbol is not set until the matrix cursor is known
+		if sym == "" {
+			msg = "  "
+		}
 		buf.WriteString(b.style.Selected.Render("["))
 		buf.WriteString(style.Render(msg))
 		buf.WriteString(b.style.Selected.Render("]"))
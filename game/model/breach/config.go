@@ -1,21 +1,198 @@
 package breach
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
 
 type SequenceConfig struct {
+	Name        string // Optional name used to refer to the sequence
+	After       string // Optional name of the sequence to upload before this one appears
 	Description string
 	Size        int
+	Points      int           // Points earned when the sequence is uploaded
+	Symbols     []string      // Optional symbol codes of the sequence, random if not set
+	Masked      int           // Number of last symbols hidden until revealed by an effect
+	Timer       time.Duration // Optional seconds to upload the sequence once it is running, it fails after
+	Effect      Effect        // Optional reward applied when the sequence is uploaded
+}
+
+// MatrixSize is the size of the matrix, it can be set in json as a single integer for a square matrix
+// or as an object with rows and columns.
+type MatrixSize struct {
+	Rows int `json:"rows"`
+	Cols int `json:"cols"`
+}
+
+// Square return the size of a square matrix
+func Square(size int) MatrixSize { return MatrixSize{Rows: size, Cols: size} }
+
+func (s *MatrixSize) UnmarshalJSON(data []byte) error {
+	var size int
+	if err := json.Unmarshal(data, &size); err == nil {
+		*s = Square(size)
+		return nil
+	}
+	type matrixSize MatrixSize // Avoid recursive call of UnmarshalJSON
+	var res matrixSize
+	if err := json.Unmarshal(data, &res); err != nil {
+		return fmt.Errorf("matrix size should be an integer or an object with rows and cols: %w", err)
+	}
+	*s = MatrixSize(res)
+	return nil
 }
 
 type Config struct {
-	Buffer    int
-	Matrix    int
-	Timer     time.Duration
-	Sequences []SequenceConfig
+	Buffer        int
+	Matrix        MatrixSize
+	Grid          [][]string // Optional symbol codes of the matrix, random if not set
+	Alphabet      Alphabet   // Optional symbols of the breach, DefaultAlphabet if not set
+	Timer         time.Duration
+	StartOnSelect bool          // Start the timer on the first selected symbol instead of breach start
+	Undo          bool          // Allow to undo the last selections
+	UndoCost      time.Duration // Timer seconds lost on each undo
+	Ordered       bool          // Each sequence starts once the previous one is uploaded
+	Seed          int64         // Seed of the random source generating the matrix and sequences
+	Traps         TrapConfig    // Optional trap cells hidden in the matrix
+	Firewall      time.Duration // Seconds before unselected cells of the matrix are regenerated, disabled if not set
+	NoPreview     bool          // Hide which sequences the hovered symbol would advance, reset or complete
+	Sequences     []SequenceConfig
+	Id            int `json:"-"` // Id sent back in the end message, set by the game on load
+}
+
+// GetAlphabet return the alphabet of the breach
+func (c Config) GetAlphabet() Alphabet {
+	if len(c.Alphabet) == 0 {
+		return DefaultAlphabet
+	}
+	return c.Alphabet
+}
+
+// Generate return the matrix and sequences of the breach from the random source.
+// Random sequences are picked from a single legal path of the matrix, to be uploaded together.
+func (c Config) Generate(rng *rand.Rand) (MatrixModel, []Sequence) {
+	var matrix MatrixModel
+	if len(c.Grid) > 0 {
+		matrix = NewMatrixFromGrid(c.Grid)
+	} else {
+		matrix = NewMatrix(c.Matrix, c.GetAlphabet(), rng)
+	}
+	path := matrix.randomPath(c.Buffer, rng)
+	solution := matrix.symbolsAt(path)
+	matrix.placeTraps(c.Traps, path, rng)
+	return matrix, NewSequences(c.Sequences, c.Parents(), solution, rng)
+}
+
+// Parents return the index of the sequence to upload before each sequence starts, -1 if none.
+// A sequence with After set waits for the named sequence, otherwise for the previous one if sequences are ordered.
+func (c Config) Parents() []int {
+	res := make([]int, len(c.Sequences))
+	for i, seq := range c.Sequences {
+		res[i] = -1
+		if seq.After != "" {
+			res[i] = slices.IndexFunc(c.Sequences, func(s SequenceConfig) bool { return s.Name == seq.After })
+		} else if c.Ordered && i > 0 {
+			res[i] = i - 1
+		}
+	}
+	return res
+}
+
+// Validate check the alphabet and the hand-authored matrix and sequences of the config
+func (c Config) Validate() error {
+	alphabet := c.GetAlphabet()
+	if err := alphabet.Validate(); err != nil {
+		return fmt.Errorf("error on alphabet: %w", err)
+	}
+	if c.Firewall < 0 {
+		return fmt.Errorf("firewall interval should not be negative, got %d", c.Firewall)
+	}
+	if err := c.Traps.Validate(); err != nil {
+		return err
+	}
+	if c.Buffer <= 0 {
+		return fmt.Errorf("buffer size should be positive, got %d", c.Buffer)
+	}
+	if len(c.Grid) == 0 && (c.Matrix.Rows <= 0 || c.Matrix.Cols <= 0) {
+		return fmt.Errorf("matrix size should be positive, got %dx%d", c.Matrix.Rows, c.Matrix.Cols)
+	}
+	for i, row := range c.Grid {
+		if len(row) == 0 || len(row) != len(c.Grid[0]) {
+			return fmt.Errorf("matrix row %d has %d symbols, expected %d", i, len(row), len(c.Grid[0]))
+		}
+		if _, err := alphabet.ParseAll(row); err != nil {
+			return fmt.Errorf("error on matrix row %d: %w", i, err)
+		}
+	}
+	// Buffer can be extended by sequences effects
+	buffer := c.Buffer
+	for _, seq := range c.Sequences {
+		if seq.Effect.Type == BufferEffect {
+			buffer += seq.Effect.Value
+		}
+	}
+	names := make(map[string]bool)
+	for _, seq := range c.Sequences {
+		if seq.Name != "" && names[seq.Name] {
+			return fmt.Errorf("sequence name %q is used twice", seq.Name)
+		}
+		names[seq.Name] = true
+	}
+	parents := c.Parents()
+	for i, seq := range c.Sequences {
+		if seq.After != "" && parents[i] < 0 {
+			return fmt.Errorf("sequence %d waits for unknown sequence %q", i, seq.After)
+		}
+		// Follow parents, a cycle never reach a sequence without parent
+		for p, n := parents[i], 0; p >= 0; p, n = parents[p], n+1 {
+			if n >= len(c.Sequences) {
+				return fmt.Errorf("sequence %d waits for itself", i)
+			}
+		}
+	}
+	for i, seq := range c.Sequences {
+		if _, err := alphabet.ParseAll(seq.Symbols); err != nil {
+			return fmt.Errorf("error on sequence %d: %w", i, err)
+		}
+		if len(seq.Symbols) > buffer {
+			return fmt.Errorf("sequence %d has %d symbols, buffer size is %d", i, len(seq.Symbols), buffer)
+		}
+		if len(seq.Symbols) == 0 && seq.Size <= 0 {
+			return fmt.Errorf("sequence %d size should be positive, got %d", i, seq.Size)
+		}
+		// Random sequences are picked from a path of the buffer size, they would be shortened
+		if len(seq.Symbols) == 0 && seq.Size > c.Buffer {
+			return fmt.Errorf("sequence %d size is %d, buffer size is %d", i, seq.Size, c.Buffer)
+		}
+		// Random sequences waiting for each other are picked one after the other in the same path
+		size := 0
+		for p := i; p >= 0; p = parents[p] {
+			if len(c.Sequences[p].Symbols) == 0 {
+				size += c.Sequences[p].Size
+			}
+		}
+		if size > c.Buffer {
+			return fmt.Errorf("sequence %d and the sequences it waits for have %d random symbols, buffer size is %d", i, size, c.Buffer)
+		}
+		if seq.Timer < 0 {
+			return fmt.Errorf("sequence %d timer should not be negative, got %d", i, seq.Timer)
+		}
+		if seq.Masked < 0 {
+			return fmt.Errorf("sequence %d masked symbols should not be negative, got %d", i, seq.Masked)
+		}
+		if err := seq.Effect.Validate(i, len(c.Sequences)); err != nil {
+			return fmt.Errorf("error on sequence %d: %w", i, err)
+		}
+	}
+	return nil
 }
 
 var DefaultConfig = Config{
-	Matrix: 5,
+	Matrix: Square(5),
 	Buffer: 10,
 	Timer:  40 * time.Second,
 	Sequences: []SequenceConfig{
@@ -0,0 +1,96 @@
+package breach
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/franciscolkdo/breach-protocol/game/clock"
+)
+
+type EffectType string
+
+const (
+	NoEffect     EffectType = ""
+	TimeEffect   EffectType = "time"   // Add Value seconds to the timer
+	BufferEffect EffectType = "buffer" // Add Value blocks to the buffer
+	RevealEffect EffectType = "reveal" // Reveal Value hidden symbols of the Target sequence
+	FreezeEffect EffectType = "freeze" // Freeze the timer for Value seconds
+	TrapsEffect  EffectType = "traps"  // Reveal all traps of the matrix
+)
+
+// Effect is a reward applied when a sequence is uploaded
+type Effect struct {
+	Type   EffectType
+	Value  int
+	Target int // Index of the sequence to reveal
+}
+
+// Validate check the effect of the sequence id, among size sequences
+func (e Effect) Validate(id, size int) error {
+	switch e.Type {
+	case NoEffect, TrapsEffect:
+		return nil
+	case TimeEffect, BufferEffect, FreezeEffect:
+	case RevealEffect:
+		if e.Target < 0 || e.Target >= size || e.Target == id {
+			return fmt.Errorf("reveal target should be another sequence, got %d", e.Target)
+		}
+	default:
+		return fmt.Errorf("unknown effect: %q", e.Type)
+	}
+	if e.Value <= 0 {
+		return fmt.Errorf("effect %s value should be positive, got %d", e.Type, e.Value)
+	}
+	return nil
+}
+
+func (e Effect) String() string {
+	switch e.Type {
+	case TimeEffect:
+		return fmt.Sprintf("+%ds", e.Value)
+	case BufferEffect:
+		return fmt.Sprintf("+%d buffer", e.Value)
+	case RevealEffect:
+		return fmt.Sprintf("reveal #%d", e.Target+1)
+	case FreezeEffect:
+		return fmt.Sprintf("freeze %ds", e.Value)
+	case TrapsEffect:
+		return "reveal traps"
+	}
+	return ""
+}
+
+// thaw move on the running freezes with the clock, the timer counts down again at the end of all freezes
+func (m *Model) thaw() {
+	var left []time.Duration
+	for _, d := range m.freezes {
+		if d -= clock.Step; d > 0 {
+			left = append(left, d)
+		}
+	}
+	m.freezes = left
+}
+
+// applyEffect apply the effect of the uploaded sequence, only once.
+// An effect can not be taken back, so the upload and the picks before it can not be undone anymore.
+func (m Model) applyEffect(seq Sequence) Model {
+	e := seq.GetEffect()
+	if e.Type == NoEffect || m.effects[seq.Id] {
+		return m
+	}
+	m.effects[seq.Id] = true
+	m.history = nil
+	switch e.Type {
+	case TimeEffect:
+		m.timer.Timeout += time.Duration(e.Value) * time.Second
+	case BufferEffect:
+		m.buffer.Extend(e.Value)
+	case RevealEffect:
+		m.sequences[e.Target].Reveal(e.Value)
+	case TrapsEffect:
+		m.matrix.reveal = true
+	case FreezeEffect:
+		m.freezes = append(m.freezes, time.Duration(e.Value)*time.Second)
+	}
+	return m
+}
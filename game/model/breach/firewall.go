@@ -0,0 +1,31 @@
+package breach
+
+import (
+	"math/rand"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// reshuffle regenerate all the cells not selected yet
+func (m *MatrixModel) reshuffle(alphabet Alphabet, rng *rand.Rand) {
+	for _, row := range m.data {
+		for x, sym := range row {
+			if sym != XXX {
+				row[x] = alphabet.newSymbols(1, rng)[0]
+			}
+		}
+	}
+}
+
+// firewallEnabled return true if the matrix is reshuffled over time
+func (m Model) firewallEnabled() bool { return m.firewallInterval > 0 }
+
+// reshuffle regenerate the matrix when the firewall timer is ended and start a new countdown.
+// The firewall timer keep ticking after timeout, so resetting the timeout is enough to restart it.
+func (m Model) reshuffle() (tea.Model, tea.Cmd) {
+	m.matrix.reshuffle(m.alphabet, m.rng)
+	m.buffer.SetCurrentSymbol(m.matrix.GetSymbol())
+	m.previewSymbol()
+	m.firewall.Timeout = m.firewallInterval
+	return m, nil
+}
@@ -1,6 +1,7 @@
 package breach
 
 import (
+	"math/rand"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -25,11 +26,16 @@ type MatrixModel struct {
 	x      int
 	y      int
 	axe    Axe
+	traps  map[Coordinate]Penalty
+	reveal bool // Show trap cells
 	keyMap keymap.KeyMap
 	style  MatrixStyle
 }
 
-func (m MatrixModel) GetSymbol() Symbol { return m.data[m.y][m.x] }
+func (m MatrixModel) GetSymbol() Symbol       { return m.data[m.y][m.x] }
+func (m MatrixModel) GetData() [][]Symbol     { return m.data }
+func (m MatrixModel) GetPosition() Coordinate { return Coordinate{X: m.x, Y: m.y} }
+func (m MatrixModel) GetAxe() Axe             { return m.axe }
 
 func (m MatrixModel) SetSymbol(s Symbol) { m.data[m.y][m.x] = s }
 
@@ -72,7 +78,17 @@ func (m MatrixModel) applySymbol() (MatrixModel, tea.Cmd) {
 	}
 	m.SetSymbol(XXX)
 	m.rotateAxe()
-	return m, OnSymbol(sym, true)
+	if trap, ok := m.traps[m.GetPosition()]; ok {
+		return m, OnTrap(sym, m.GetPosition(), trap)
+	}
+	return m, OnSymbol(sym, true, m.GetPosition())
+}
+
+// restore put back the symbol of an undone selection and return to the previous axe
+func (m *MatrixModel) restore(pos Coordinate, sym Symbol) {
+	m.data[pos.Y][pos.X] = sym
+	m.x, m.y = pos.X, pos.Y
+	m.rotateAxe()
 }
 
 func (m MatrixModel) Init() tea.Cmd { return nil }
@@ -83,24 +99,59 @@ func (m MatrixModel) Update(msg tea.Msg) (MatrixModel, tea.Cmd) {
 		switch {
 		case key.Matches(msg, m.keyMap.Right):
 			m.setX(1)
-			return m, OnSymbol(m.GetSymbol(), false)
+			return m, OnSymbol(m.GetSymbol(), false, m.GetPosition())
 		case key.Matches(msg, m.keyMap.Left):
 			m.setX(-1)
-			return m, OnSymbol(m.GetSymbol(), false)
+			return m, OnSymbol(m.GetSymbol(), false, m.GetPosition())
 		case key.Matches(msg, m.keyMap.Up):
 			m.setY(-1)
-			return m, OnSymbol(m.GetSymbol(), false)
+			return m, OnSymbol(m.GetSymbol(), false, m.GetPosition())
 		case key.Matches(msg, m.keyMap.Down):
 			m.setY(+1)
-			return m, OnSymbol(m.GetSymbol(), false)
+			return m, OnSymbol(m.GetSymbol(), false, m.GetPosition())
 		case key.Matches(msg, m.keyMap.Select):
 			return m.applySymbol()
 		}
+	case tea.MouseMsg:
+		return m.onMouse(msg)
+	}
+	return m, nil
+}
+
+// cellAt return the cell shown at the position of the matrix view
+func (m MatrixModel) cellAt(x, y int) (Coordinate, bool) {
+	left, top := style.SpaceBoxPosition(matrixTitle, m.content(), lipgloss.Center, 0)
+	x, y = x-left, y-top
+	// Each symbol is 2 cells wide, followed by a space
+	if x < 0 || y < 0 || y >= len(m.data) || x%3 == 2 || x/3 >= len(m.data[y]) {
+		return Coordinate{}, false
+	}
+	return Coordinate{X: x / 3, Y: y}, true
+}
+
+// onMouse move the cursor on the hovered cell of the current axe, and select it on click
+func (m MatrixModel) onMouse(msg tea.MouseMsg) (MatrixModel, tea.Cmd) {
+	pos, ok := m.cellAt(msg.X, msg.Y)
+	if !ok || (m.axe == X && pos.Y != m.y) || (m.axe == Y && pos.X != m.x) {
+		return m, nil
+	}
+	moved := pos != m.GetPosition()
+	m.x, m.y = pos.X, pos.Y
+	switch {
+	case msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft:
+		return m.applySymbol()
+	case moved:
+		return m, OnSymbol(m.GetSymbol(), false, m.GetPosition())
 	}
 	return m, nil
 }
 
 func (m MatrixModel) View() string {
+	return style.SpaceBox(matrixTitle, m.content(), lipgloss.Center)
+}
+
+// content return the symbols of the matrix
+func (m MatrixModel) content() string {
 	var s strings.Builder
 	for i, symbols := range m.data {
 		for j, sym := range symbols {
@@ -118,33 +169,54 @@ func (m MatrixModel) View() string {
 				s.WriteString(m.style.CurrentAxe.Render(msg))
 			case i == m.y && m.axe == X:
 				s.WriteString(m.style.CurrentAxe.Render(msg))
+			case m.reveal && m.traps[Coordinate{X: j, Y: i}].Type != NoPenalty && sym != XXX:
+				s.WriteString(m.style.Trap.Render(msg))
 			default:
 				s.WriteString(m.style.InactiveSymbol.Render(msg))
 			}
-			s.WriteString(style.RootStyle.Render(" "))
+			// No trailing space, lipgloss trims it on the last line which would break the alignment
+			if j < len(symbols)-1 {
+				s.WriteString(style.RootStyle.Render(" "))
+			}
 		}
 		if i < len(m.data)-1 {
 			tools.NewLine(&s)
 		}
 	}
-	return style.SpaceBox(matrixTitle, s.String(), lipgloss.Center)
+	return s.String()
 }
 
 type MatrixStyle struct {
 	CurrentSymbol  lipgloss.Style
 	InactiveSymbol lipgloss.Style
 	CurrentAxe     lipgloss.Style
+	Trap           lipgloss.Style
 }
 
-func NewMatrix(size int) MatrixModel {
-	m := make([][]Symbol, size)
+func NewMatrix(size MatrixSize, alphabet Alphabet, rng *rand.Rand) MatrixModel {
+	m := make([][]Symbol, size.Rows)
 	for i := range m {
-		m[i] = newSymbols(size)
+		m[i] = alphabet.newSymbols(size.Cols, rng)
 	}
+	return NewMatrixFromData(m)
+}
+
+// NewMatrixFromGrid return a matrix with the given symbol codes, codes are checked on config validation
+func NewMatrixFromGrid(grid [][]string) MatrixModel {
+	data := make([][]Symbol, len(grid))
+	for i, row := range grid {
+		data[i] = toSymbols(row)
+	}
+	return NewMatrixFromData(data)
+}
+
+// NewMatrixFromData return a matrix with the given symbols
+func NewMatrixFromData(data [][]Symbol) MatrixModel {
 	matrix := MatrixModel{
-		data: m,
-		x:    0,
-		y:    0,
+		data:  data,
+		x:     0,
+		y:     0,
+		traps: make(map[Coordinate]Penalty),
 
 		axe:    X,
 		keyMap: keymap.DefaultKeyMap(),
@@ -152,6 +224,7 @@ func NewMatrix(size int) MatrixModel {
 			CurrentSymbol:  style.RootStyle.Foreground(style.NeonPink).Bold(true),
 			InactiveSymbol: style.RootStyle.Foreground(style.Indigo),
 			CurrentAxe:     style.RootStyle.Foreground(style.NeonCyan),
+			Trap:           style.RootStyle.Foreground(style.DarkRed).Bold(true),
 		},
 	}
 	matrix.setKeymap()
@@ -0,0 +1,71 @@
+package breach
+
+import "math/rand"
+
+// pathSearchBudget limit the number of steps when looking for a path, to avoid long searches on small matrices
+const pathSearchBudget = 10000
+
+// Coordinate is a cell position in the code matrix
+type Coordinate struct {
+	X int
+	Y int
+}
+
+// candidates return all cells reachable from pos on the given axe
+func (m MatrixModel) candidates(pos Coordinate, axe Axe, rng *rand.Rand) []Coordinate {
+	var res []Coordinate
+	if axe == X {
+		for x := range m.data[pos.Y] {
+			res = append(res, Coordinate{X: x, Y: pos.Y})
+		}
+	} else {
+		for y := range m.data {
+			res = append(res, Coordinate{X: pos.X, Y: y})
+		}
+	}
+	rng.Shuffle(len(res), func(i, j int) { res[i], res[j] = res[j], res[i] })
+	return res
+}
+
+// randomPath walk a legal path through the matrix from the cursor position, alternating axes like a player would.
+// It returns the coordinates of each pick, the path can be shorter than length if the matrix is too small.
+func (m MatrixModel) randomPath(length int, rng *rand.Rand) []Coordinate {
+	used := make(map[Coordinate]bool)
+	var best []Coordinate
+	budget := pathSearchBudget
+
+	var walk func(path []Coordinate, pos Coordinate, axe Axe) bool
+	walk = func(path []Coordinate, pos Coordinate, axe Axe) bool {
+		if len(path) > len(best) {
+			best = append([]Coordinate(nil), path...)
+		}
+		if len(path) >= length {
+			return true
+		}
+		if budget--; budget < 0 {
+			return false
+		}
+		for _, next := range m.candidates(pos, axe, rng) {
+			if used[next] || m.data[next.Y][next.X] == XXX {
+				continue
+			}
+			used[next] = true
+			if walk(append(path, next), next, 1-axe) {
+				return true
+			}
+			used[next] = false
+		}
+		return false
+	}
+	walk(nil, Coordinate{X: m.x, Y: m.y}, m.axe)
+	return best
+}
+
+// symbolsAt return the symbols found on each coordinate of the path
+func (m MatrixModel) symbolsAt(path []Coordinate) []Symbol {
+	res := make([]Symbol, len(path))
+	for i, c := range path {
+		res[i] = m.data[c.Y][c.X]
+	}
+	return res
+}
@@ -1,11 +1,15 @@
 package breach
 
 import (
+	"math/rand"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/franciscolkdo/breach-protocol/game/clock"
 	"github.com/franciscolkdo/breach-protocol/game/style"
+	"golang.org/x/exp/slices"
 )
 
 const seqMax = 10
@@ -16,6 +20,8 @@ const (
 	SequenceFailed SequenceStatus = iota
 	SequenceSuccess
 	SequenceRunning
+	SequenceLocked // Waiting for the previous sequence to be uploaded
+	SequenceHidden // Not shown until the parent sequence is uploaded
 )
 
 type SequenceStatusMsg struct {
@@ -36,6 +42,14 @@ type Sequence struct {
 	status      SequenceStatus
 	description string
 	points      int
+	hidden      int // Number of last symbols not shown
+	parent      int // Id of the sequence to upload before this one starts, -1 if none
+	effect      Effect
+	timer       clock.Timer
+	timed       bool // Sequence fails when timer is ended
+	started     bool // Timer is started
+	expired     bool // Sequence failed because its timer is ended
+	next        int  // Position after the hovered symbol, -1 if no preview
 	style       SequenceStyle
 }
 
@@ -45,15 +59,62 @@ func (s Sequence) GetStatus() SequenceStatus { return s.status }
 func (s Sequence) GetPoints() int            { return s.points }
 func (s Sequence) IsDone() bool              { return s.status < SequenceRunning }
 func (s Sequence) Last() int                 { return len(s.data) - s.x }
+func (s Sequence) GetEffect() Effect         { return s.effect }
+func (s Sequence) GetParent() int            { return s.parent }
+func (s Sequence) IsExpired() bool           { return s.expired }
+func (s Sequence) IsWaiting() bool           { return s.status == SequenceLocked || s.status == SequenceHidden }
 
+// StartTimer start the expiry timer of the sequence, only once.
+// A sequence running again after its timer expired is failed.
+func (s *Sequence) StartTimer() tea.Cmd {
+	if !s.timed {
+		return nil
+	}
+	if s.started {
+		return s.expire()
+	}
+	s.started = true
+	return s.timer.Init()
+}
+
+// keepTimer keep the expiry timer of the current sequence when the sequence is restored by an undo
+func (s *Sequence) keepTimer(current Sequence) tea.Cmd {
+	s.timer, s.started = current.timer, current.started
+	if !s.timed {
+		return nil
+	}
+	return s.expire()
+}
+
+// expire fail the running sequence if its timer is ended
+func (s *Sequence) expire() tea.Cmd {
+	if s.status != SequenceRunning || !s.timer.Timedout() {
+		return nil
+	}
+	s.status = SequenceFailed
+	s.expired = true
+	return OnSequenceStatusMsg(s.Id, SequenceFailed)
+}
+
+// Reveal show the next n hidden symbols
+func (s *Sequence) Reveal(n int) {
+	s.hidden -= n
+	if s.hidden < 0 {
+		s.hidden = 0
+	}
+}
+
+// VerifySymbol advance the sequence with the new symbol.
+// On mismatch, the position falls back to the longest sequence prefix still matching the last symbols,
+// so a sequence is uploaded as soon as it appears anywhere in the buffer.
 func (s *Sequence) VerifySymbol(sym Symbol) tea.Cmd {
-	if s.x >= len(s.data) {
+	if s.status != SequenceRunning || s.x >= len(s.data) {
 		return nil
 	}
 	if s.data[s.x] == sym {
 		s.x++
 	} else {
-		s.x = 0
+		s.x = s.fallback(sym)
 	}
 	if s.x >= len(s.data) {
 		s.status = SequenceSuccess
@@ -62,6 +123,31 @@ func (s *Sequence) VerifySymbol(sym Symbol) tea.Cmd {
 	return nil
 }
 
+// preview set the position the sequence would reach with the hovered symbol.
+// Masked symbols are not previewed, it would reveal them.
+func (s *Sequence) preview(sym Symbol) {
+	s.next = -1
+	if s.status != SequenceRunning || sym == XXX || sym == ICE || s.x >= len(s.data)-s.hidden {
+		return
+	}
+	if s.data[s.x] == sym {
+		s.next = s.x + 1
+	} else {
+		s.next = s.fallback(sym)
+	}
+}
+
+// fallback return the length of the longest sequence prefix which is a suffix of the matched symbols followed by sym
+func (s Sequence) fallback(sym Symbol) int {
+	matched := append(append([]Symbol(nil), s.data[:s.x]...), sym)
+	for k := len(matched) - 1; k > 0; k-- {
+		if slices.Equal(matched[len(matched)-k:], s.data[:k]) {
+			return k
+		}
+	}
+	return 0
+}
+
 func (s Sequence) Init() tea.Cmd { return nil }
 
 func (s Sequence) Update(msg tea.Msg) (Sequence, tea.Cmd) {
@@ -73,28 +159,66 @@ func (s Sequence) Update(msg tea.Msg) (Sequence, tea.Cmd) {
 		}
 	case SymbolMsg:
 		if msg.selected {
+			s.next = -1
 			return s, s.VerifySymbol(msg.symbol)
 		}
+		s.preview(msg.symbol)
+	case clock.TickMsg, clock.StartStopMsg:
+		var cmd tea.Cmd
+		s.timer, cmd = s.timer.Update(msg)
+		return s, cmd
+	// Sequence is failed if not uploaded before the end of its timer
+	case clock.TimeoutMsg:
+		if msg.ID == s.timer.ID() {
+			return s, s.expire()
+		}
 	}
 	return s, nil
 }
 
 func (s Sequence) View() string {
 	var res strings.Builder
-	alignDesc := style.RootStyle.Render(strings.Repeat("   ", seqMax-len(s.data)))
-	if s.status == SequenceRunning {
+	alignDesc := style.RootStyle.Render(strings.Repeat("   ", max(0, seqMax-len(s.data))))
+	switch s.status {
+	case SequenceHidden:
+		res.WriteString(s.style.Locked.Render(strings.Repeat("?? ", len(s.data))))
+		res.WriteString(alignDesc + s.style.Locked.Render("Hidden sequence"))
+	case SequenceLocked:
+		for _, sym := range s.data {
+			res.WriteString(s.style.Locked.Render(sym.String() + " "))
+		}
+		res.WriteString(alignDesc + s.style.Locked.Render(s.description+" (locked)"))
+	case SequenceRunning:
 		for i, sym := range s.data {
-			if i < s.x {
-				res.WriteString(s.style.ValidatedSymbol.Render(sym.String()))
-			} else if i == s.x {
-				res.WriteString(s.style.CurrentSymbol.Render(sym.String()))
-			} else {
-				res.WriteString(s.style.NextSymbol.Render(sym.String()))
+			msg := sym.String()
+			if i >= len(s.data)-s.hidden {
+				msg = "??"
+			}
+			switch {
+			// Validated symbols lost with the hovered symbol
+			case i < s.x && s.next >= 0 && i >= s.next:
+				res.WriteString(s.style.ResetSymbol.Render(msg))
+			case i < s.x:
+				res.WriteString(s.style.ValidatedSymbol.Render(msg))
+			case i == s.x && s.next == len(s.data):
+				res.WriteString(s.style.CompleteSymbol.Render(msg))
+			case i == s.x && s.next > s.x:
+				res.WriteString(s.style.AdvanceSymbol.Render(msg))
+			case i == s.x:
+				res.WriteString(s.style.CurrentSymbol.Render(msg))
+			default:
+				res.WriteString(s.style.NextSymbol.Render(msg))
 			}
 			res.WriteString(style.RootStyle.Render(" "))
 		}
 		res.WriteString(alignDesc + style.RootStyle.Render(s.description))
-	} else {
+		if s.timed {
+			res.WriteString(s.style.Timer.Render(" (" + s.timer.View() + ")"))
+		}
+		if s.effect.Type != NoEffect {
+			res.WriteString(s.style.Effect.Render(" [" + s.effect.String() + "]"))
+		}
+	default:
 		style := s.style.Success
 		if s.status == SequenceFailed {
 			style = s.style.Failed
@@ -113,29 +237,123 @@ type SequenceStyle struct {
 	NextSymbol      lipgloss.Style
 	Failed          lipgloss.Style
 	Success         lipgloss.Style
+	Effect          lipgloss.Style
+	Locked          lipgloss.Style
+	Timer           lipgloss.Style
+	AdvanceSymbol   lipgloss.Style // Current symbol matched by the hovered symbol
+	CompleteSymbol  lipgloss.Style // Last symbol matched by the hovered symbol
+	ResetSymbol     lipgloss.Style // Validated symbol lost with the hovered symbol
 }
 
-func NewSequence(cfg SequenceConfig, id int) Sequence {
+// NewSequence return a sequence with the given symbols.
+// The sequence waits for its parent to be uploaded if any, hidden if the config set a parent by name.
+func NewSequence(cfg SequenceConfig, id int, data []Symbol, parent int) Sequence {
+	status := SequenceRunning
+	if parent >= 0 {
+		status = SequenceLocked
+		if cfg.After != "" {
+			status = SequenceHidden
+		}
+	}
 	return Sequence{
 		Id:          id,
-		data:        newSymbols(cfg.Size),
+		data:        data,
 		x:           0,
-		status:      SequenceRunning,
+		status:      status,
+		parent:      parent,
 		description: cfg.Description,
+		points:      cfg.Points,
+		hidden:      cfg.Masked,
+		effect:      cfg.Effect,
+		timer:       clock.NewTimer(cfg.Timer*time.Second, time.Second),
+		timed:       cfg.Timer > 0,
+		next:        -1,
 		style: SequenceStyle{
 			CurrentSymbol:   style.RootStyle.Foreground(style.NeonPink).Bold(true),
 			ValidatedSymbol: style.RootStyle.Foreground(style.LimeGreen),
 			NextSymbol:      style.RootStyle.Foreground(style.NeonCyan),
 			Failed:          style.RootStyle.Foreground(style.DarkRed).Bold(true),
 			Success:         style.RootStyle.Foreground(style.VividGreen).Bold(true),
+			Effect:          style.RootStyle.Foreground(style.MetallicGold),
+			Locked:          style.RootStyle.Foreground(style.Indigo),
+			Timer:           style.RootStyle.Foreground(style.NeonMagenta),
+			AdvanceSymbol:   style.RootStyle.Foreground(style.LimeGreen).Bold(true).Underline(true),
+			CompleteSymbol:  style.RootStyle.Foreground(style.BrightGold).Bold(true).Underline(true),
+			ResetSymbol:     style.RootStyle.Foreground(style.DarkRed).Bold(true),
 		},
 	}
 }
 
-func NewSequences(cfg []SequenceConfig) []Sequence {
+// NewSequences return sequences picked from the solution symbols, each sequence is a random part of the solution.
+// As the solution is a legal path in the matrix, every sequence can be uploaded within the buffer.
+// A sequence with a parent is picked after the end of its parent in the solution,
+// and each sequence leaves enough space after it for its children.
+// Sequences are shortened only if the matrix is too small for a solution of the buffer size.
+// Sequences with symbols set in config are used as is.
+func NewSequences(cfg []SequenceConfig, parents []int, solution []Symbol, rng *rand.Rand) []Sequence {
 	res := make([]Sequence, len(cfg))
-	for i, seq := range cfg {
-		res[i] = NewSequence(seq, i)
+	ends := make([]int, len(cfg)) // End of each sequence in the solution
+	tails := childrenSizes(cfg, parents)
+	done := make([]bool, len(cfg))
+	// Parents are created before their children, config validation ensure there is no cycle
+	for created := 0; created < len(cfg); {
+		for i, seq := range cfg {
+			parent := parents[i]
+			if done[i] || (parent >= 0 && !done[parent]) {
+				continue
+			}
+			done[i] = true
+			created++
+			if len(seq.Symbols) > 0 {
+				// Symbols are checked on config validation
+				res[i] = NewSequence(seq, i, toSymbols(seq.Symbols), parent)
+				continue
+			}
+			start := 0
+			if parent >= 0 {
+				start = ends[parent]
+			}
+			size := seq.Size
+			if room := len(solution) - start - tails[i]; size > room {
+				size = max(room, 1)
+			}
+			if size > len(solution) {
+				size = len(solution)
+			}
+			if start > len(solution)-size {
+				start = len(solution) - size
+			}
+			offset := start
+			if free := len(solution) - tails[i] - size - start; free > 0 {
+				offset += rng.Intn(free + 1)
+			}
+			ends[i] = offset + size
+			res[i] = NewSequence(seq, i, append([]Symbol(nil), solution[offset:offset+size]...), parent)
+		}
 	}
 	return res
 }
+
+// childrenSizes return the number of solution symbols needed after each sequence by its longest chain of children.
+// Sequences with symbols set in config are not picked from the solution.
+func childrenSizes(cfg []SequenceConfig, parents []int) []int {
+	res := make([]int, len(cfg))
+	for i := range cfg {
+		// Config validation ensure there is no cycle
+		size := 0
+		for c, p := i, parents[i]; p >= 0; c, p = p, parents[p] {
+			if len(cfg[c].Symbols) == 0 {
+				size += cfg[c].Size
+			}
+			res[p] = max(res[p], size)
+		}
+	}
+	return res
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
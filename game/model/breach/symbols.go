@@ -1,42 +1,157 @@
-//go:generate stringer -type=Symbol -linecomment
 package breach
 
 import (
+	"encoding/json"
+	"fmt"
 	"math/rand"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
-type Symbol int
+// Symbol is the 2 characters code of a matrix cell
+type Symbol string
 
 const (
-	X55 Symbol = iota // 55
-	XBD               // BD
-	XE9               // E9
-	X7A               // 7A
-	X1C               // 1C
-	delim_end
-	XXX // XX
+	X55 Symbol = "55"
+	XBD Symbol = "BD"
+	XE9 Symbol = "E9"
+	X7A Symbol = "7A"
+	X1C Symbol = "1C"
+	XXX Symbol = "XX" // Already selected cell, can not be part of an alphabet
+	ICE Symbol = "##" // Corrupted block written by a trap, can not be part of an alphabet
 )
 
+func (s Symbol) String() string { return string(s) }
+
 type SymbolMsg struct {
 	symbol   Symbol
 	selected bool
+	pos      Coordinate // Position of the symbol in the matrix
+	trap     Penalty    // Penalty of the cell if it is a trap
 }
 
-func OnSymbol(symbol Symbol, selected bool) tea.Cmd {
+func OnSymbol(symbol Symbol, selected bool, pos Coordinate) tea.Cmd {
 	return func() tea.Msg {
 		return SymbolMsg{
 			symbol:   symbol,
 			selected: selected,
+			pos:      pos,
+		}
+	}
+}
+
+// OnTrap is sent when a trap cell is selected
+func OnTrap(symbol Symbol, pos Coordinate, trap Penalty) tea.Cmd {
+	return func() tea.Msg {
+		return SymbolMsg{
+			symbol:   symbol,
+			selected: true,
+			pos:      pos,
+			trap:     trap,
+		}
+	}
+}
+
+// SymbolWeight is a symbol of the alphabet, the weight set how often the symbol appears in the matrix.
+// It can be set in json as a single code with a weight of 1 or as an object with code and weight.
+type SymbolWeight struct {
+	Code   Symbol `json:"code"`
+	Weight int    `json:"weight"`
+}
+
+func (s *SymbolWeight) UnmarshalJSON(data []byte) error {
+	var code string
+	if err := json.Unmarshal(data, &code); err == nil {
+		*s = SymbolWeight{Code: Symbol(code), Weight: 1}
+		return nil
+	}
+	type symbolWeight SymbolWeight // Avoid recursive call of UnmarshalJSON
+	res := symbolWeight{Weight: 1}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return fmt.Errorf("symbol should be a code or an object with code and weight: %w", err)
+	}
+	*s = SymbolWeight(res)
+	return nil
+}
+
+// Alphabet is the list of symbols used in a breach
+type Alphabet []SymbolWeight
+
+var DefaultAlphabet = Alphabet{
+	{Code: X55, Weight: 1},
+	{Code: XBD, Weight: 1},
+	{Code: XE9, Weight: 1},
+	{Code: X7A, Weight: 1},
+	{Code: X1C, Weight: 1},
+}
+
+// Validate check all codes are unique 2 characters symbols with a positive weight
+func (a Alphabet) Validate() error {
+	seen := make(map[Symbol]bool)
+	for _, sym := range a {
+		switch {
+		case lipgloss.Width(sym.Code.String()) != 2:
+			return fmt.Errorf("symbol %q should be 2 characters wide", sym.Code)
+		case sym.Code == XXX || sym.Code == ICE:
+			return fmt.Errorf("symbol %q is reserved for selected cells", sym.Code)
+		case seen[sym.Code]:
+			return fmt.Errorf("symbol %q is defined twice", sym.Code)
+		case sym.Weight <= 0:
+			return fmt.Errorf("symbol %q weight should be positive, got %d", sym.Code, sym.Weight)
 		}
+		seen[sym.Code] = true
 	}
+	return nil
 }
 
-func newSymbols(size int) []Symbol {
+// Parse return the symbol matching the code
+func (a Alphabet) Parse(code string) (Symbol, error) {
+	for _, sym := range a {
+		if sym.Code.String() == code {
+			return sym.Code, nil
+		}
+	}
+	return "", fmt.Errorf("unknown symbol: %q", code)
+}
+
+// ParseAll return the symbols matching the codes
+func (a Alphabet) ParseAll(codes []string) ([]Symbol, error) {
+	res := make([]Symbol, len(codes))
+	for i, code := range codes {
+		sym, err := a.Parse(code)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = sym
+	}
+	return res, nil
+}
+
+// newSymbols return random symbols of the alphabet according to their weights
+func (a Alphabet) newSymbols(size int, rng *rand.Rand) []Symbol {
+	var total int
+	for _, sym := range a {
+		total += sym.Weight
+	}
 	s := make([]Symbol, size)
 	for i := 0; i < len(s); i++ {
-		s[i] = Symbol(rand.Intn(int(delim_end))) // Symbols "delim_end" and "XXX" does not count
+		n := rng.Intn(total)
+		for _, sym := range a {
+			if n -= sym.Weight; n < 0 {
+				s[i] = sym.Code
+				break
+			}
+		}
 	}
 	return s
 }
+
+// toSymbols convert codes already checked with the alphabet
+func toSymbols(codes []string) []Symbol {
+	res := make([]Symbol, len(codes))
+	for i, code := range codes {
+		res[i] = Symbol(code)
+	}
+	return res
+}
@@ -0,0 +1,121 @@
+package breach
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type PenaltyType string
+
+const (
+	NoPenalty       PenaltyType = ""
+	TimePenalty     PenaltyType = "time"     // Remove Value seconds from the timer
+	BufferPenalty   PenaltyType = "buffer"   // Fill Value buffer blocks with corrupted symbols
+	SequencePenalty PenaltyType = "sequence" // Fail a random running sequence
+)
+
+// Penalty is applied when a trap cell is selected
+type Penalty struct {
+	Type  PenaltyType
+	Value int
+}
+
+// TrapConfig set the trap cells (ICE) hidden in the matrix
+type TrapConfig struct {
+	Density   float64   // Ratio of matrix cells being traps, between 0 and 1
+	Penalties []Penalty // Penalties picked randomly for each trap
+}
+
+// Validate check the density and penalties of traps
+func (c TrapConfig) Validate() error {
+	if c.Density < 0 || c.Density > 1 {
+		return fmt.Errorf("trap density should be between 0 and 1, got %v", c.Density)
+	}
+	if c.Density > 0 && len(c.Penalties) == 0 {
+		return fmt.Errorf("traps should have at least one penalty")
+	}
+	for _, p := range c.Penalties {
+		switch p.Type {
+		case TimePenalty, BufferPenalty:
+			if p.Value <= 0 {
+				return fmt.Errorf("penalty %s value should be positive, got %d", p.Type, p.Value)
+			}
+		case SequencePenalty:
+		default:
+			return fmt.Errorf("unknown penalty: %q", p.Type)
+		}
+	}
+	return nil
+}
+
+// placeTraps hide traps in the matrix, out of the solution path to keep the breach winnable
+func (m *MatrixModel) placeTraps(cfg TrapConfig, path []Coordinate, rng *rand.Rand) {
+	m.traps = make(map[Coordinate]Penalty)
+	if cfg.Density <= 0 || len(cfg.Penalties) == 0 {
+		return
+	}
+	onPath := make(map[Coordinate]bool)
+	for _, c := range path {
+		onPath[c] = true
+	}
+	var cells []Coordinate
+	for y, row := range m.data {
+		for x := range row {
+			if c := (Coordinate{X: x, Y: y}); !onPath[c] {
+				cells = append(cells, c)
+			}
+		}
+	}
+	rng.Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
+	size := int(math.Round(cfg.Density * float64(len(m.data)*len(m.data[0]))))
+	if size > len(cells) {
+		size = len(cells)
+	}
+	for _, c := range cells[:size] {
+		m.traps[c] = cfg.Penalties[rng.Intn(len(cfg.Penalties))]
+	}
+}
+
+// applyPenalty apply the penalty of a selected trap
+func (m Model) applyPenalty(p Penalty) (Model, tea.Cmd) {
+	switch p.Type {
+	case TimePenalty:
+		m.timer.Timeout -= time.Duration(p.Value) * time.Second
+		// Timer does not send timeout message when stopped by the penalty
+		if m.timer.Timedout() {
+			res, cmd := m.isOver(TimerDone)
+			return res.(Model), cmd
+		}
+	case BufferPenalty:
+		var cmds []tea.Cmd
+		for i := 0; i < p.Value && m.buffer.GetEmptySize() > 0; i++ {
+			var cmd tea.Cmd
+			m.buffer.SetCurrentSymbol(ICE)
+			m.buffer, cmd = m.buffer.UseBufferBlock()
+			cmds = append(cmds, cmd)
+			for i := range m.sequences {
+				cmds = append(cmds, m.sequences[i].VerifySymbol(ICE))
+			}
+		}
+		m.buffer.SetCurrentSymbol(m.matrix.GetSymbol())
+		return m, tea.Batch(cmds...)
+	case SequencePenalty:
+		var running []int
+		for i, seq := range m.sequences {
+			// Locked and hidden sequences are not started yet
+			if seq.GetStatus() == SequenceRunning {
+				running = append(running, i)
+			}
+		}
+		if len(running) > 0 {
+			i := running[m.rng.Intn(len(running))]
+			m.sequences[i].status = SequenceFailed
+			return m, OnSequenceStatusMsg(m.sequences[i].Id, SequenceFailed)
+		}
+	}
+	return m, nil
+}
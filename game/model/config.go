@@ -1,8 +1,10 @@
 package model
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/franciscolkdo/breach-protocol/game/model/breach"
@@ -19,27 +21,116 @@ const (
 )
 
 type Config struct {
-	Type   model           `json:"type"`
-	Config json.RawMessage `json:"config"`
+	Type       model           `json:"type"`
+	Config     json.RawMessage `json:"config"`
+	Checkpoint bool            `json:"checkpoint,omitempty"` // Restart the campaign from this model once reached
 }
 
-func (m Config) Load() (tea.Model, error) {
+// Levels is the list of models played in a run
+type Levels interface {
+	Len() int                    // Number of models, negative for an endless run
+	Get(idx int) (Config, error) // Config of the model at idx
+}
+
+// Campaign is the list of models read from the config file
+type Campaign []Config
+
+func (c Campaign) Len() int                    { return len(c) }
+func (c Campaign) Get(idx int) (Config, error) { return c[idx], nil }
+
+// NewConfig return the config of a model of type t, cfg is encoded as its json config
+func NewConfig(t string, cfg any) (Config, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return Config{}, fmt.Errorf("error on encoding %s config: %w", t, err)
+	}
+	res := Config{Type: model(t), Config: data}
+	return res, res.Validate()
+}
+
+// State is the state of the run given to loaded models
+type State struct {
+	Id    int   // Id of the loaded model, sent back when it ends
+	Seed  int64 // Seed of the run
+	Level int   // Index of the model in the run
+	Score int   // Score of the run
+}
+
+// LevelSeed return the seed of a level of the run.
+// Run seed and level are hashed together, so neighbouring run seeds do not share levels.
+func LevelSeed(seed int64, level int) int64 {
+	h := fnv.New64a()
+	_ = binary.Write(h, binary.LittleEndian, [2]int64{seed, int64(level)})
+	return int64(h.Sum64())
+}
+
+// Load return the model described by the config
+func (m Config) Load(state State) (tea.Model, error) {
 	switch m.Type {
 	case breachModel:
-		return newModel(breach.NewModel, m.Config)
+		return newModel(func(cfg breach.Config) tea.Model {
+			// Each model get its own seed derived from the run seed, to be replayable
+			if cfg.Seed == 0 {
+				cfg.Seed = LevelSeed(state.Seed, state.Level)
+			}
+			cfg.Id = state.Id
+			return breach.NewModel(cfg)
+		}, m.Config)
 	case storyModel:
-		return newModel(story.NewModel, m.Config)
+		return newModel(func(cfg story.Config) tea.Model {
+			cfg.Id = state.Id
+			return story.NewModel(cfg)
+		}, m.Config)
 	case endModel:
-		return newModel(end.NewModel, m.Config)
+		return newModel(func(cfg end.Config) tea.Model {
+			cfg.Seed = state.Seed
+			cfg.Score = state.Score
+			return end.NewModel(cfg)
+		}, m.Config)
 	default:
 		return nil, fmt.Errorf("model not found for config: %s", m.Type)
 	}
 }
 
-func newModel[T any](cb func(T) tea.Model, config json.RawMessage) (tea.Model, error) {
+// Validate check the config can be loaded, without creating the model
+func (m Config) Validate() error {
+	var err error
+	switch m.Type {
+	case breachModel:
+		_, err = decode[breach.Config](m.Config)
+	case storyModel:
+		_, err = decode[story.Config](m.Config)
+	case endModel:
+		_, err = decode[end.Config](m.Config)
+	default:
+		err = fmt.Errorf("model not found for config: %s", m.Type)
+	}
+	return err
+}
+
+// validator is implemented by configs checking their content on load
+type validator interface {
+	Validate() error
+}
+
+// decode unmarshal and validate the config
+func decode[T any](config json.RawMessage) (T, error) {
 	var cfg T
 	if err := json.Unmarshal(config, &cfg); err != nil {
-		return nil, fmt.Errorf("error on loading config: %w", err)
+		return cfg, fmt.Errorf("error on loading config: %w", err)
+	}
+	if v, ok := any(cfg).(validator); ok {
+		if err := v.Validate(); err != nil {
+			return cfg, fmt.Errorf("invalid config: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+func newModel[T any](cb func(T) tea.Model, config json.RawMessage) (tea.Model, error) {
+	cfg, err := decode[T](config)
+	if err != nil {
+		return nil, err
 	}
 	return cb(cfg), nil
 }
@@ -1,7 +1,15 @@
 package end
 
+import "github.com/franciscolkdo/breach-protocol/game/highscore"
+
 type Config struct {
-	Msg string
+	Msg       string
+	Seed      int64              // Seed of the run, shown to replay it
+	Score     int                // Score of the run
+	Level     int                // Level reached in an endless run, not shown if 0
+	Retry     bool               // Allow to retry the failed level
+	NoRestart bool               // Hide restart, the run can be played only once
+	Scores    []highscore.Record // Best runs of the campaign, shown in a table if set
 }
 
 var DefaultConfig = Config{
@@ -2,11 +2,14 @@
 package end
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/franciscolkdo/breach-protocol/game/highscore"
 	"github.com/franciscolkdo/breach-protocol/game/keymap"
 	"github.com/franciscolkdo/breach-protocol/game/style"
 	"github.com/franciscolkdo/breach-protocol/tools"
@@ -21,6 +24,7 @@ type EndGameMsg int
 const (
 	Quit EndGameMsg = iota
 	Restart
+	Retry // Retry level
 )
 
 func OnEndGameMsg(msg EndGameMsg) tea.Cmd {
@@ -31,6 +35,10 @@ func OnEndGameMsg(msg EndGameMsg) tea.Cmd {
 
 type Model struct {
 	msg           string
+	seed          int64
+	score         int
+	level         int
+	scores        []highscore.Record
 	keyMap        keymap.KeyMap
 	options       []EndGameMsg
 	currentOption int
@@ -60,40 +68,114 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keyMap.Select):
 			return m, OnEndGameMsg(m.options[m.currentOption])
 		}
+	case tea.MouseMsg:
+		return m.onMouse(msg)
+	}
+	return m, nil
+}
+
+// onMouse set the hovered option as current, and select it on click
+func (m Model) onMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	content, line := m.content()
+	x, y := style.SpaceBoxPosition(title, content, lipgloss.Center, line)
+	if msg.Y < y || msg.Y >= y+lipgloss.Height(m.optionView(0)) {
+		return m, nil
+	}
+	for i := range m.options {
+		width := lipgloss.Width(m.optionView(i))
+		if msg.X >= x && msg.X < x+width {
+			m.currentOption = i
+			if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+				return m, OnEndGameMsg(m.options[i])
+			}
+			return m, nil
+		}
+		x += width
 	}
 	return m, nil
 }
 
 func (m Model) View() string {
+	content, _ := m.content()
+	return style.SpaceBox(title, content, lipgloss.Center)
+}
+
+// scoresView return the table of best runs
+func (m Model) scoresView() string {
+	var s strings.Builder
+	s.WriteString(m.style.Score.Render(fmt.Sprintf("Top %d", len(m.scores))))
+	tools.NewLine(&s)
+	s.WriteString(m.style.Active.Render(fmt.Sprintf("%3s %6s %6s %6s  %-10s", "#", "Score", "Time", "Buffer", "Date")))
+	for i, r := range m.scores {
+		tools.NewLine(&s)
+		s.WriteString(m.style.Inactive.Render(fmt.Sprintf("%3d %6d %6s %6d  %-10s", i+1, r.Score, r.Remaining.Round(time.Second), r.Buffer, r.Date.Format(time.DateOnly))))
+	}
+	return s.String()
+}
+
+// optionView return the view of the option i
+func (m Model) optionView(i int) string {
+	style := m.style.Inactive
+	if i == m.currentOption {
+		style = m.style.Active
+	}
+	return style.Border(lipgloss.NormalBorder()).Render(m.options[i].String())
+}
+
+// content return the content of the end screen and the line where options start
+func (m Model) content() (string, int) {
 	var s strings.Builder
 	s.WriteString(m.msg)
 	tools.NewLine(&s)
+	s.WriteString(m.style.Score.Render(fmt.Sprintf("Score: %d", m.score)))
+	tools.NewLine(&s)
+	if m.level > 0 {
+		s.WriteString(m.style.Score.Render(fmt.Sprintf("Level reached: %d", m.level)))
+		tools.NewLine(&s)
+	}
+	s.WriteString(m.style.Inactive.Render(fmt.Sprintf("Seed: %d", m.seed)))
+	tools.NewLine(&s)
+	if len(m.scores) > 0 {
+		tools.NewLine(&s)
+		s.WriteString(m.scoresView())
+		tools.NewLine(&s)
+	}
+	line := strings.Count(s.String(), "\n")
 	var opt []string
 	for i := 0; i < len(m.options); i++ {
-		style := m.style.Inactive
-		if i == m.currentOption {
-			style = m.style.Active
-		}
-		opt = append(opt, style.Border(lipgloss.NormalBorder()).Render(m.options[i].String()))
+		opt = append(opt, m.optionView(i))
 	}
 	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Center, opt...))
-	return style.SpaceBox(title, s.String(), lipgloss.Center)
+	return s.String(), line
 }
 
 type EndGameStyle struct {
 	Inactive lipgloss.Style
 	Active   lipgloss.Style
+	Score    lipgloss.Style
 }
 
 func NewModel(cfg Config) tea.Model {
+	options := []EndGameMsg{Restart, Quit}
+	if cfg.NoRestart {
+		options = []EndGameMsg{Quit}
+	}
+	if cfg.Retry {
+		options = append([]EndGameMsg{Retry}, options...)
+	}
 	return Model{
 		msg:           cfg.Msg,
+		seed:          cfg.Seed,
+		score:         cfg.Score,
+		level:         cfg.Level,
+		scores:        cfg.Scores,
 		keyMap:        keymap.DefaultKeyMap(),
 		currentOption: 0,
-		options:       []EndGameMsg{Restart, Quit},
+		options:       options,
 		style: EndGameStyle{
 			Inactive: style.RootStyle.Foreground(style.Indigo),
 			Active:   style.RootStyle.Foreground(style.NeonPink).Bold(true),
+			Score:    style.RootStyle.Foreground(style.MetallicGold).Bold(true),
 		},
 	}
 }
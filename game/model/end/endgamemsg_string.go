@@ -10,11 +10,12 @@ func _() {
 	var x [1]struct{}
 	_ = x[Quit-0]
 	_ = x[Restart-1]
+	_ = x[Retry-2]
 }
 
-const _EndGameMsg_name = "QuitRestart"
+const _EndGameMsg_name = "QuitRestartRetry level"
 
-var _EndGameMsg_index = [...]uint8{0, 4, 11}
+var _EndGameMsg_index = [...]uint8{0, 4, 11, 22}
 
 func (i EndGameMsg) String() string {
 	if i < 0 || i >= EndGameMsg(len(_EndGameMsg_index)-1) {
@@ -22,8 +22,9 @@ type Replica struct {
 
 type Config struct {
 	Type StoryType `json:"type"`
-	Text string    `json:"text"`
-	Chat []Replica `json:"chat"`
+	Text string    `json:"text,omitempty"`
+	Chat []Replica `json:"chat,omitempty"`
+	Id   int       `json:"-"` // Id sent back in the end message, set by the game on load
 }
 
 // newReader return a string reader with rendered text
@@ -7,45 +7,48 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscolkdo/breach-protocol/game/clock"
 	"github.com/franciscolkdo/breach-protocol/game/keymap"
 	"github.com/franciscolkdo/breach-protocol/game/message"
 )
 
 var _ tea.Model = Model{}
 
+// letterDelay is the clock time between two letters written
+const letterDelay = 30 * time.Millisecond
+
 // Model is a model to show text story
 type Model struct {
+	id      int
 	text    io.RuneReader
 	output  []rune
 	isended bool
 
-	keyMap keymap.KeyMap
-	ticker *time.Ticker
-}
-
-type tickMsg struct{}
-
-func (m Model) OnTick() tea.Cmd {
-	return func() tea.Msg {
-		<-m.ticker.C
-		return tickMsg{}
-	}
+	keyMap  keymap.KeyMap
+	elapsed time.Duration // Clock time since the last letter
 }
 
 // Init initializes the StoryModel.
 func (m Model) Init() tea.Cmd {
-	return m.OnTick()
+	return nil
 }
 
 func (m Model) setEndReadingState() tea.Model {
-	m.ticker.Stop()
 	m.isended = true
 	return m
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
-	case tickMsg:
+	case clock.TickMsg:
+		if m.isended {
+			return m, nil
+		}
+		m.elapsed += clock.Step
+		if m.elapsed < letterDelay {
+			return m, nil
+		}
+		m.elapsed -= letterDelay
 		for { // Don't count white space in writing effet
 			r, _, err := m.text.ReadRune()
 			if err != nil {
@@ -58,7 +61,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				break
 			}
 		}
-		return m, m.OnTick()
+		return m, nil
 	case tea.KeyMsg:
 		if key.Matches(msg, m.keyMap.Select) {
 			if !m.isended {
@@ -72,7 +75,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.output = append(m.output, r)
 				}
 			}
-			return m, message.OnEndViewMsg(message.EndModelMsg{Status: message.Success})
+			return m, message.OnEndViewMsg(message.EndModelMsg{Id: m.id, Status: message.Success})
 		}
 	}
 	return m, nil
@@ -85,9 +88,9 @@ func (m Model) View() string {
 // NewModel return a breach model instance
 func NewModel(cfg Config) tea.Model {
 	return Model{
+		id:      cfg.Id,
 		text:    cfg.newReader(),
 		isended: false,
 		keyMap:  keymap.DefaultKeyMap(),
-		ticker:  time.NewTicker(30 * time.Millisecond),
 	}
 }
@@ -0,0 +1,164 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/franciscolkdo/breach-protocol/game/clock"
+)
+
+// Replay is a recorded run, inputs are played back on the same clock ticks as they were received
+type Replay struct {
+	Mode   string        `json:"mode"`           // Command which started the run
+	Date   string        `json:"date,omitempty"` // Date of the daily challenge
+	Start  Save          `json:"start"`          // Campaign hash, seed and progress when the run started
+	End    time.Duration `json:"end"`            // Clock time when the run was quit
+	Inputs []Input       `json:"inputs"`
+}
+
+// Input is a key or a mouse message given to the game, at the clock time it was received
+type Input struct {
+	At    time.Duration `json:"at"`
+	Key   *Key          `json:"key,omitempty"`
+	Mouse *Mouse        `json:"mouse,omitempty"`
+}
+
+// Key is a recorded key message
+type Key struct {
+	Type  tea.KeyType `json:"type"`
+	Runes string      `json:"runes,omitempty"`
+	Alt   bool        `json:"alt,omitempty"`
+}
+
+// Mouse is a recorded mouse message, relative to the current model view so it does not depend on the terminal size
+type Mouse struct {
+	X      int             `json:"x"`
+	Y      int             `json:"y"`
+	Action tea.MouseAction `json:"action"`
+	Button tea.MouseButton `json:"button"`
+}
+
+// msg return the message of the input
+func (i Input) msg() tea.Msg {
+	if i.Mouse != nil {
+		return tea.MouseMsg{X: i.Mouse.X, Y: i.Mouse.Y, Action: i.Mouse.Action, Button: i.Mouse.Button}
+	}
+	return tea.KeyMsg{Type: i.Key.Type, Runes: []rune(i.Key.Runes), Alt: i.Key.Alt}
+}
+
+// ReadReplay return the replay of the file
+func ReadReplay(path string) (Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Replay{}, fmt.Errorf("error on reading replay: %w", err)
+	}
+	var r Replay
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Replay{}, fmt.Errorf("error on decoding replay: %w", err)
+	}
+	for i, in := range r.Inputs {
+		if in.Key == nil && in.Mouse == nil {
+			return Replay{}, fmt.Errorf("input %d of replay has no key nor mouse", i)
+		}
+	}
+	return r, nil
+}
+
+// Write save the replay in the file
+func (r Replay) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "    ")
+	if err != nil {
+		return fmt.Errorf("error on encoding replay: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("error on writing replay: %w", err)
+	}
+	return nil
+}
+
+// WithRecording return the game recording the inputs of the player into the replay
+func (m Model) WithRecording(r Replay) Model {
+	r.Inputs = nil
+	m.record = &r
+	return m
+}
+
+// GetReplay return the recorded run until now, false if the game is not recorded
+func (m Model) GetReplay() (Replay, bool) {
+	if m.record == nil {
+		return Replay{}, false
+	}
+	r := *m.record
+	r.End = m.clock.GetTime()
+	return r, true
+}
+
+// WithReplay return the game playing back the replay from its start, speed times faster than the recorded run.
+// Inputs of the player are ignored, except quit.
+func (m Model) WithReplay(r Replay, speed float64) Model {
+	m.playback = &r
+	m.clock = clock.New(speed)
+	return m.resume(r.Start)
+}
+
+// recordInput add the key or mouse message to the recorded inputs
+func (m Model) recordInput(msg tea.Msg) {
+	if m.record == nil {
+		return
+	}
+	in := Input{At: m.clock.GetTime()}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		in.Key = &Key{Type: msg.Type, Runes: string(msg.Runes), Alt: msg.Alt}
+	case tea.MouseMsg:
+		in.Mouse = &Mouse{X: msg.X, Y: msg.Y, Action: msg.Action, Button: msg.Button}
+	}
+	m.record.Inputs = append(m.record.Inputs, in)
+}
+
+// playMsg play back the inputs received until the clock time
+type playMsg struct{}
+
+// nextPlay return the command playing back the inputs received on this tick.
+// Like the inputs of the player, they come after the messages of the tick and before the next one.
+func (m Model) nextPlay() tea.Cmd {
+	if m.playback == nil || len(m.playback.Inputs) == 0 || m.playback.Inputs[0].At > m.clock.GetTime() {
+		return nil
+	}
+	return tea.Tick(m.clock.GetDelay()/2, func(time.Time) tea.Msg {
+		return playMsg{}
+	})
+}
+
+// playInputs give the inputs received until the clock time to the current model
+func (m *Model) playInputs() tea.Cmd {
+	var cmds []tea.Cmd
+	for len(m.playback.Inputs) > 0 && m.playback.Inputs[0].At <= m.clock.GetTime() {
+		switch msg := m.playback.Inputs[0].msg().(type) {
+		case tea.KeyMsg:
+			cmds = append(cmds, m.onKey(msg))
+		case tea.MouseMsg:
+			var cmd tea.Cmd
+			m.current, cmd = m.current.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		m.playback.Inputs = m.playback.Inputs[1:]
+	}
+	return tea.Batch(cmds...)
+}
+
+// replayEnded return true if the clock reached the end of the replay
+func (m Model) replayEnded() bool {
+	return m.playback != nil && m.clock.GetTime() >= m.playback.End
+}
+
+// replayView return the replay state shown in the header
+func (m Model) replayView() string {
+	if m.replayEnded() {
+		return "Replay ended"
+	}
+	return fmt.Sprintf("Replay x%g", m.clock.GetSpeed())
+}
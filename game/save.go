@@ -0,0 +1,74 @@
+package game
+
+import (
+	"time"
+
+	"github.com/franciscolkdo/breach-protocol/storage"
+)
+
+// saveFile is the file of the data directory keeping the campaign progress
+const saveFile = "save.json"
+
+// Save is the progress of a campaign, written each time a model is done or a life is lost
+type Save struct {
+	Campaign   string        `json:"campaign"` // Hash of the campaign file
+	Path       string        `json:"path"`     // Path of the campaign file, empty for the default campaign
+	Index      int           `json:"index"`    // Index of the next model to play
+	Score      int           `json:"score"`
+	Remaining  time.Duration `json:"remaining"` // Time left on timers of done breaches
+	Buffer     int           `json:"buffer"`    // Buffer slots used in done breaches
+	Seed       int64         `json:"seed"`
+	Checkpoint Checkpoint    `json:"checkpoint"` // Last checkpoint reached
+	Lives      int           `json:"lives"`      // Lives left, 0 if disabled
+}
+
+// ReadSave return the saved campaign progress, false if there is none
+func ReadSave() (Save, bool, error) {
+	var s *Save
+	if err := storage.Read(saveFile, &s); err != nil {
+		return Save{}, false, err
+	}
+	if s == nil {
+		return Save{}, false, nil
+	}
+	return *s, true, nil
+}
+
+// WithSave return the game resumed from the save, progress is saved each time a model is done or a life is lost
+func (m Model) WithSave(s Save) Model {
+	m.save = &s
+	return m.resume(s)
+}
+
+// resume return the game at the progress of the save
+func (m Model) resume(s Save) Model {
+	m.currentIdx = s.Index
+	m.score = s.Score
+	m.remaining, m.buffer = s.Remaining, s.Buffer
+	m.seed = s.Seed
+	m.checkpoint = s.Checkpoint
+	if s.Lives > 0 {
+		m.lives = s.Lives
+	} else if m.maxLives > 0 {
+		// Game over was saved, the campaign restarts from the checkpoint
+		m.restart()
+	}
+	_ = m.LoadModel()
+	return m
+}
+
+// writeSave save the progress of the campaign, the save is removed once the campaign is completed.
+// Errors are ignored, the game goes on without saving.
+func (m Model) writeSave() {
+	if m.save == nil {
+		return
+	}
+	if !m.endless() && m.currentIdx >= m.levels.Len() {
+		_ = storage.Remove(saveFile)
+		return
+	}
+	s := *m.save
+	s.Index, s.Score, s.Seed, s.Checkpoint, s.Lives = m.currentIdx, m.score, m.seed, m.checkpoint, m.lives
+	s.Remaining, s.Buffer = m.remaining, m.buffer
+	_ = storage.Write(saveFile, s)
+}
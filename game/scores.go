@@ -0,0 +1,41 @@
+package game
+
+import (
+	"time"
+
+	"github.com/franciscolkdo/breach-protocol/game/highscore"
+	"github.com/franciscolkdo/breach-protocol/game/message"
+)
+
+// WithScores return the game recording high scores of the campaign and of each breach
+func (m Model) WithScores(campaign, name string) Model {
+	m.records = &highscore.Record{Campaign: campaign, Name: name}
+	return m
+}
+
+// recordLevel save the high score of the breach done.
+// Errors are ignored, the game goes on without recording.
+func (m Model) recordLevel(msg message.EndModelMsg) {
+	if m.records == nil {
+		return
+	}
+	r := *m.records
+	r.Level, r.Score, r.Remaining, r.Buffer, r.Date = m.currentIdx, msg.Points, msg.Remaining, msg.Buffer, time.Now()
+	_ = highscore.Add(r)
+}
+
+// recordRun save the high score of the ended run and return the best records of the campaign, with this one
+func (m Model) recordRun() []highscore.Record {
+	if m.records == nil {
+		return nil
+	}
+	r := *m.records
+	r.Level, r.Score, r.Remaining, r.Buffer, r.Date = highscore.Campaign, m.score, m.remaining, m.buffer, time.Now()
+	_ = highscore.Add(r)
+	records, _ := highscore.Read()
+	records = highscore.Filter(records, r.Campaign, highscore.Campaign)
+	if len(records) > highscore.Top {
+		records = records[:highscore.Top]
+	}
+	return records
+}
@@ -0,0 +1,82 @@
+package solver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/franciscolkdo/breach-protocol/game/model/breach"
+)
+
+// SequenceConfig describe a sequence of a puzzle
+type SequenceConfig struct {
+	Description string   `json:"description"`
+	Symbols     []string `json:"symbols"`
+	Points      int      `json:"points"`
+}
+
+// Puzzle describe a breach to solve, symbols are given with their codes
+type Puzzle struct {
+	Matrix    [][]string       `json:"matrix"`
+	Buffer    int              `json:"buffer"`
+	Alphabet  breach.Alphabet  `json:"alphabet"` // Optional, default alphabet of the game if not set
+	Sequences []SequenceConfig `json:"sequences"`
+}
+
+// ReadPuzzle decode a puzzle from json
+func ReadPuzzle(r io.Reader) (Puzzle, error) {
+	var p Puzzle
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return Puzzle{}, fmt.Errorf("error on decoding puzzle: %w", err)
+	}
+	return p, nil
+}
+
+// Load return the matrix and sequences of the puzzle
+func (p Puzzle) Load() (breach.MatrixModel, []breach.Sequence, error) {
+	alphabet := p.Alphabet
+	if len(alphabet) == 0 {
+		alphabet = breach.DefaultAlphabet
+	}
+	if err := alphabet.Validate(); err != nil {
+		return breach.MatrixModel{}, nil, fmt.Errorf("error on alphabet: %w", err)
+	}
+	if p.Buffer <= 0 {
+		return breach.MatrixModel{}, nil, fmt.Errorf("buffer size should be positive, got %d", p.Buffer)
+	}
+	if len(p.Matrix) == 0 {
+		return breach.MatrixModel{}, nil, fmt.Errorf("matrix is empty")
+	}
+	data := make([][]breach.Symbol, len(p.Matrix))
+	for i, row := range p.Matrix {
+		if len(row) != len(p.Matrix[0]) {
+			return breach.MatrixModel{}, nil, fmt.Errorf("matrix row %d has %d symbols, expected %d", i, len(row), len(p.Matrix[0]))
+		}
+		symbols, err := alphabet.ParseAll(row)
+		if err != nil {
+			return breach.MatrixModel{}, nil, fmt.Errorf("error on matrix row %d: %w", i, err)
+		}
+		data[i] = symbols
+	}
+	sequences := make([]breach.Sequence, len(p.Sequences))
+	for i, seq := range p.Sequences {
+		if len(seq.Symbols) == 0 {
+			return breach.MatrixModel{}, nil, fmt.Errorf("sequence %d is empty", i)
+		}
+		symbols, err := alphabet.ParseAll(seq.Symbols)
+		if err != nil {
+			return breach.MatrixModel{}, nil, fmt.Errorf("error on sequence %d: %w", i, err)
+		}
+		sequences[i] = breach.NewSequence(breach.SequenceConfig{Description: seq.Description, Size: len(symbols), Points: seq.Points}, i, symbols, -1)
+	}
+	return breach.NewMatrixFromData(data), sequences, nil
+}
+
+// Solve return the best solution of the puzzle, starting on the first row like in game
+func (p Puzzle) Solve() (Solution, error) {
+	matrix, sequences, err := p.Load()
+	if err != nil {
+		return Solution{}, err
+	}
+	return Solve(matrix, p.Buffer, breach.X, sequences), nil
+}
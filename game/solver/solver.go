@@ -0,0 +1,331 @@
+package solver
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/franciscolkdo/breach-protocol/game/model/breach"
+	"golang.org/x/exp/slices"
+)
+
+// Solution is the best path found for a breach
+type Solution struct {
+	Path     []breach.Coordinate // Coordinates of each pick in the matrix
+	Buffer   []breach.Symbol     // Symbols saved in the buffer
+	Uploaded []int               // Ids of uploaded sequences
+	Points   int                 // Sum of points of uploaded sequences
+	Complete bool                // All sequences are uploaded
+}
+
+// pattern is a list of symbols to write in the buffer, built from one or more sequences.
+// Free picks can be added before each index where gap is true, to move in the matrix.
+type pattern struct {
+	data   []breach.Symbol
+	gap    []bool
+	points int
+}
+
+// maxFree is the maximum number of consecutive free picks between two sequences.
+// Two free picks are enough to reach any row, more picks are only useful to go around already used cells.
+const maxFree = 4
+
+type solver struct {
+	data      [][]breach.Symbol
+	start     breach.Coordinate
+	axe       breach.Axe
+	buffer    int
+	sequences []breach.Sequence
+	width     int
+	used      []uint64 // Bitset of picked cells
+	path      []breach.Coordinate
+	counts    map[breach.Symbol]int // Number of cells of each symbol in the matrix
+	reach     map[int]bool          // States known to reach the end of the current pattern when used cells are ignored
+}
+
+// Solve return the highest scoring legal path for the matrix, starting from the matrix cursor on the given axe.
+// On equal points, the shortest path is returned.
+func Solve(matrix breach.MatrixModel, buffer int, axe breach.Axe, sequences []breach.Sequence) Solution {
+	s := solver{
+		data:      matrix.GetData(),
+		start:     matrix.GetPosition(),
+		axe:       axe,
+		buffer:    buffer,
+		sequences: sequences,
+	}
+	for _, row := range s.data {
+		if len(row) > s.width {
+			s.width = len(row)
+		}
+	}
+	s.used = make([]uint64, (s.width*len(s.data)+63)/64)
+	s.counts = make(map[breach.Symbol]int)
+	for _, row := range s.data {
+		for _, sym := range row {
+			s.counts[sym]++
+		}
+	}
+
+	best := s.solution(nil)
+	for _, p := range s.patterns() {
+		// Patterns are sorted by points, no better solution can be found
+		if p.points < best.Points {
+			break
+		}
+		if p.points == best.Points && len(best.Path) > 0 && len(p.data) >= len(best.Path) {
+			continue
+		}
+		if path, ok := s.find(p); ok {
+			sol := s.solution(path)
+			if sol.Points > best.Points || (sol.Points == best.Points && (len(best.Path) == 0 || len(sol.Path) < len(best.Path))) {
+				best = sol
+			}
+		}
+	}
+	return best
+}
+
+// solution return the solution of the given path
+func (s solver) solution(path []breach.Coordinate) Solution {
+	sol := Solution{Path: path, Buffer: make([]breach.Symbol, len(path)), Complete: true}
+	for i, c := range path {
+		sol.Buffer[i] = s.data[c.Y][c.X]
+	}
+	for _, seq := range s.sequences {
+		if contains(sol.Buffer, seq.GetData()) {
+			sol.Uploaded = append(sol.Uploaded, seq.Id)
+			sol.Points += seq.GetPoints()
+		} else {
+			sol.Complete = false
+		}
+	}
+	return sol
+}
+
+// patterns return all the ways to chain the sequences in the buffer, sorted by points
+func (s solver) patterns() []pattern {
+	var res []pattern
+	seen := make(map[string]bool)
+	var build func(p pattern, done []bool)
+	build = func(p pattern, done []bool) {
+		for i, seq := range s.sequences {
+			data := seq.GetData()
+			if done[i] || len(data) == 0 {
+				continue
+			}
+			done[i] = true
+			// Sequences already in the pattern do not need to be added
+			if contains(p.data, data) {
+				build(p, done)
+				done[i] = false
+				continue
+			}
+			for _, o := range overlaps(p.data, data) {
+				if len(p.data)+len(data)-o > s.buffer {
+					continue
+				}
+				next := pattern{
+					data: append(slices.Clone(p.data), data[o:]...),
+					gap:  append(slices.Clone(p.gap), make([]bool, len(data)-o)...),
+				}
+				// Free picks are only allowed between sequences which do not overlap
+				if o == 0 {
+					next.gap[len(p.data)] = true
+				}
+				// Patterns needing more cells of a symbol than the matrix has can not be written, nor the longer ones
+				if !s.available(next.data) {
+					continue
+				}
+				if key := next.key(); !seen[key] {
+					seen[key] = true
+					next.points = s.points(next.data)
+					res = append(res, next)
+				}
+				build(next, done)
+			}
+			done[i] = false
+		}
+	}
+	build(pattern{}, make([]bool, len(s.sequences)))
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].points != res[j].points {
+			return res[i].points > res[j].points
+		}
+		return len(res[i].data) < len(res[j].data)
+	})
+	return res
+}
+
+// available return true if the matrix has enough cells of each symbol to write data
+func (s solver) available(data []breach.Symbol) bool {
+	need := make(map[breach.Symbol]int)
+	for _, sym := range data {
+		if need[sym]++; need[sym] > s.counts[sym] {
+			return false
+		}
+	}
+	return true
+}
+
+// points return the sum of points of sequences found in data
+func (s solver) points(data []breach.Symbol) int {
+	var res int
+	for _, seq := range s.sequences {
+		if contains(data, seq.GetData()) {
+			res += seq.GetPoints()
+		}
+	}
+	return res
+}
+
+// find look for a legal path writing the pattern in the buffer
+func (s *solver) find(p pattern) ([]breach.Coordinate, bool) {
+	s.path = s.path[:0]
+	s.reach = make(map[int]bool)
+	for i := range s.used {
+		s.used[i] = 0
+	}
+	if !s.walk(p, s.start, s.axe, 0, s.buffer-len(p.data), 0) {
+		return nil, false
+	}
+	return slices.Clone(s.path), true
+}
+
+// walk pick the next symbol of the pattern on the current axe, or a free symbol when the pattern allow it.
+// slack is the number of free picks left in the buffer, run the number of consecutive free picks.
+func (s *solver) walk(p pattern, pos breach.Coordinate, axe breach.Axe, i, slack, run int) bool {
+	if i >= len(p.data) {
+		return true
+	}
+	if !s.reachable(p, pos, axe, i, slack, run) {
+		return false
+	}
+	for _, c := range s.candidates(pos, axe) {
+		sym := s.data[c.Y][c.X]
+		if s.isUsed(c) || sym == breach.XXX {
+			continue
+		}
+		match := sym == p.data[i]
+		free := p.gap[i] && slack > 0 && run < maxFree
+		if !match && !free {
+			continue
+		}
+		s.setUsed(c, true)
+		s.path = append(s.path, c)
+		if match && s.walk(p, c, 1-axe, i+1, slack, 0) {
+			return true
+		}
+		if free && s.walk(p, c, 1-axe, i, slack-1, run+1) {
+			return true
+		}
+		s.path = s.path[:len(s.path)-1]
+		s.setUsed(c, false)
+	}
+	return false
+}
+
+// reachable return true if the end of the pattern can be reached from the state when used cells are ignored.
+// Ignoring used cells only adds paths, so a state failing here fails in the walk whatever the used cells are.
+// Unlike the walk, the result only depends on the state and is kept for the whole pattern.
+func (s *solver) reachable(p pattern, pos breach.Coordinate, axe breach.Axe, i, slack, run int) bool {
+	if i >= len(p.data) {
+		return true
+	}
+	key := s.key(pos, axe, i, slack, run)
+	if res, ok := s.reach[key]; ok {
+		return res
+	}
+	res := false
+	for _, c := range s.candidates(pos, axe) {
+		sym := s.data[c.Y][c.X]
+		if sym == breach.XXX {
+			continue
+		}
+		if sym == p.data[i] && s.reachable(p, c, 1-axe, i+1, slack, 0) {
+			res = true
+			break
+		}
+		if p.gap[i] && slack > 0 && run < maxFree && s.reachable(p, c, 1-axe, i, slack-1, run+1) {
+			res = true
+			break
+		}
+	}
+	s.reach[key] = res
+	return res
+}
+
+// isUsed return true if the cell is already in the path
+func (s solver) isUsed(c breach.Coordinate) bool {
+	idx := c.Y*s.width + c.X
+	return s.used[idx/64]&(1<<(idx%64)) != 0
+}
+
+// setUsed add or remove the cell from the path
+func (s *solver) setUsed(c breach.Coordinate, used bool) {
+	idx := c.Y*s.width + c.X
+	if used {
+		s.used[idx/64] |= 1 << (idx % 64)
+	} else {
+		s.used[idx/64] &^= 1 << (idx % 64)
+	}
+}
+
+// key return a unique key of the walk state, used cells excepted
+func (s solver) key(pos breach.Coordinate, axe breach.Axe, i, slack, run int) int {
+	key := pos.Y*s.width + pos.X
+	key = key*2 + int(axe)
+	key = key*(s.buffer+1) + i
+	key = key*(s.buffer+1) + slack
+	return key*(maxFree+1) + run
+}
+
+// candidates return all cells reachable from pos on the given axe
+func (s solver) candidates(pos breach.Coordinate, axe breach.Axe) []breach.Coordinate {
+	var res []breach.Coordinate
+	if axe == breach.X {
+		for x := range s.data[pos.Y] {
+			res = append(res, breach.Coordinate{X: x, Y: pos.Y})
+		}
+	} else {
+		for y := range s.data {
+			if pos.X < len(s.data[y]) {
+				res = append(res, breach.Coordinate{X: pos.X, Y: y})
+			}
+		}
+	}
+	return res
+}
+
+// key return a unique key of the pattern
+func (p pattern) key() string {
+	var s strings.Builder
+	for i, sym := range p.data {
+		if p.gap[i] {
+			s.WriteByte('+')
+		}
+		s.WriteString(sym.String())
+		s.WriteByte(0)
+	}
+	return s.String()
+}
+
+// overlaps return all the lengths where the end of data is the start of seq
+func overlaps(data, seq []breach.Symbol) []int {
+	res := []int{0}
+	for o := 1; o < len(seq) && o <= len(data); o++ {
+		if slices.Equal(data[len(data)-o:], seq[:o]) {
+			res = append(res, o)
+		}
+	}
+	return res
+}
+
+// contains return true if seq is a part of data
+func contains(data, seq []breach.Symbol) bool {
+	for i := 0; i+len(seq) <= len(data); i++ {
+		if slices.Equal(data[i:i+len(seq)], seq) {
+			return true
+		}
+	}
+	return false
+}
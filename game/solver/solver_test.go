@@ -0,0 +1,139 @@
+package solver
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/franciscolkdo/breach-protocol/game/model/breach"
+)
+
+// randomPuzzle return a puzzle with a rows x cols matrix of the given codes
+func randomPuzzle(rng *rand.Rand, rows, cols, buffer int, codes []string, sizes ...int) Puzzle {
+	p := Puzzle{Buffer: buffer}
+	for _, code := range codes {
+		p.Alphabet = append(p.Alphabet, breach.SymbolWeight{Code: breach.Symbol(code), Weight: 1})
+	}
+	p.Matrix = make([][]string, rows)
+	for y := range p.Matrix {
+		p.Matrix[y] = make([]string, cols)
+		for x := range p.Matrix[y] {
+			p.Matrix[y][x] = codes[rng.Intn(len(codes))]
+		}
+	}
+	for i, size := range sizes {
+		seq := SequenceConfig{Points: 10 * (i + 1)}
+		for j := 0; j < size; j++ {
+			seq.Symbols = append(seq.Symbols, codes[rng.Intn(len(codes))])
+		}
+		p.Sequences = append(p.Sequences, seq)
+	}
+	return p
+}
+
+// bruteForce return the best points of all legal paths of the puzzle
+func bruteForce(t *testing.T, p Puzzle) int {
+	matrix, sequences, err := p.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := matrix.GetData()
+	used := make(map[breach.Coordinate]bool)
+	var buffer []breach.Symbol
+	best := 0
+	var walk func(pos breach.Coordinate, axe breach.Axe)
+	walk = func(pos breach.Coordinate, axe breach.Axe) {
+		points := 0
+		for _, seq := range sequences {
+			if contains(buffer, seq.GetData()) {
+				points += seq.GetPoints()
+			}
+		}
+		if points > best {
+			best = points
+		}
+		if len(buffer) >= p.Buffer {
+			return
+		}
+		for i := 0; i < len(data); i++ {
+			c := breach.Coordinate{X: i, Y: pos.Y}
+			if axe == breach.Y {
+				c = breach.Coordinate{X: pos.X, Y: i}
+			}
+			if used[c] {
+				continue
+			}
+			used[c] = true
+			buffer = append(buffer, data[c.Y][c.X])
+			walk(c, 1-axe)
+			buffer = buffer[:len(buffer)-1]
+			used[c] = false
+		}
+	}
+	walk(breach.Coordinate{}, breach.X)
+	return best
+}
+
+// checkPath fail if the path is not a legal path writing the solution buffer
+func checkPath(t *testing.T, p Puzzle, sol Solution) {
+	t.Helper()
+	if len(sol.Path) > p.Buffer {
+		t.Fatalf("path of %d picks is longer than the buffer of %d", len(sol.Path), p.Buffer)
+	}
+	used := make(map[breach.Coordinate]bool)
+	pos, axe := breach.Coordinate{}, breach.X
+	for i, c := range sol.Path {
+		if used[c] || (axe == breach.X && c.Y != pos.Y) || (axe == breach.Y && c.X != pos.X) {
+			t.Fatalf("pick %d %v is not legal", i, c)
+		}
+		if string(sol.Buffer[i]) != p.Matrix[c.Y][c.X] {
+			t.Fatalf("buffer symbol %d is %s, matrix has %s", i, sol.Buffer[i], p.Matrix[c.Y][c.X])
+		}
+		used[c] = true
+		pos, axe = c, 1-axe
+	}
+}
+
+func TestSolveMatchesBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	codes := []string{"1C", "55", "7A", "BD"}
+	for i := 0; i < 300; i++ {
+		size := 3 + rng.Intn(2)
+		p := randomPuzzle(rng, size, size, 3+rng.Intn(4), codes, 2+rng.Intn(2), 2+rng.Intn(2), 2+rng.Intn(3))
+		sol, err := p.Solve()
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkPath(t, p, sol)
+		if want := bruteForce(t, p); sol.Points != want {
+			t.Fatalf("puzzle %d: solver found %d points, brute force %d: %+v", i, sol.Points, want, p)
+		}
+	}
+}
+
+// Sequences needing a symbol found once in the matrix can not be uploaded together,
+// the solver should give up on them quickly. This puzzle took seconds with a memo depending on used cells.
+func TestSolveUnreachableSequences(t *testing.T) {
+	rng := rand.New(rand.NewSource(36))
+	codes := []string{"1C", "55", "7A", "BD", "E9"}
+	p := randomPuzzle(rng, 8, 8, 12, codes, 3, 3, 3)
+	p.Alphabet = append(p.Alphabet, breach.SymbolWeight{Code: "FF", Weight: 1})
+	p.Matrix[rng.Intn(8)][rng.Intn(8)] = "FF"
+	for i := 0; i < 3; i++ {
+		symbols := []string{codes[rng.Intn(5)], codes[rng.Intn(5)], codes[rng.Intn(5)]}
+		symbols[rng.Intn(3)] = "FF"
+		p.Sequences = append(p.Sequences, SequenceConfig{Symbols: symbols, Points: 100})
+	}
+	start := time.Now()
+	sol, err := p.Solve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Fatalf("solver took %v", d)
+	}
+	checkPath(t, p, sol)
+	if sol.Points != 160 {
+		t.Fatalf("solver found %d points, expected 160", sol.Points)
+	}
+}
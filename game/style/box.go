@@ -1,14 +1,18 @@
 package style
 
 import (
+	"math"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/franciscolkdo/breach-protocol/tools"
 )
 
-func SpaceBox(title string, content string, align lipgloss.Position) string {
-	var s strings.Builder
+// spaceBoxPaddingTop and spaceBoxPaddingLeft are the padding around the whole box
+const spaceBoxPaddingTop, spaceBoxPaddingLeft = 1, 2
+
+// spaceBox return the title box and the style of the content box
+func spaceBox(title string, content string, align lipgloss.Position) (string, lipgloss.Style) {
 	// Set titleBorder
 	titleBorder := lipgloss.NormalBorder()
 	titleBorder.Top = "═"
@@ -32,9 +36,49 @@ func SpaceBox(title string, content string, align lipgloss.Position) string {
 	if lipgloss.Width(contentBox) > lipgloss.Width(titleBox) {
 		titleBox = titleStyle.Width(lipgloss.Width(contentBox) - contentStyle.GetHorizontalFrameSize()).Render(title)
 	}
+	return titleBox, contentStyle.Width(lipgloss.Width(titleBox) - contentStyle.GetHorizontalFrameSize())
+}
+
+func SpaceBox(title string, content string, align lipgloss.Position) string {
+	var s strings.Builder
+	titleBox, contentStyle := spaceBox(title, content, align)
 	s.WriteString(titleBox)
 	tools.NewLine(&s)
-	s.WriteString(contentStyle.Width(lipgloss.Width(titleBox) - contentStyle.GetHorizontalFrameSize()).Render(content))
+	s.WriteString(contentStyle.Render(content))
+
+	return RootStyle.Padding(spaceBoxPaddingTop, spaceBoxPaddingLeft, 1, spaceBoxPaddingLeft).Render(s.String())
+}
+
+// SpaceBoxPosition return the position of the first cell of the content line in the SpaceBox view
+func SpaceBoxPosition(title string, content string, align lipgloss.Position, line int) (int, int) {
+	titleBox, contentStyle := spaceBox(title, content, align)
+	lines := strings.Split(content, "\n")
+	var x int
+	if line >= 0 && line < len(lines) {
+		// Lines are aligned in the content box like lipgloss does, remainder goes on the right
+		short := contentStyle.GetWidth() - lipgloss.Width(lines[line])
+		switch align {
+		case lipgloss.Right:
+			x = short
+		case lipgloss.Center:
+			x = short / 2
+		}
+	}
+	return spaceBoxPaddingLeft + contentStyle.GetBorderLeftSize() + x, spaceBoxPaddingTop + lipgloss.Height(titleBox) + line
+}
 
-	return RootStyle.Padding(1, 2, 1, 2).Render(s.String())
+// PlaceOffset return the offset of a block placed with gap free cells around it, like lipgloss.Place does
+func PlaceOffset(gap int, pos lipgloss.Position) int {
+	if gap <= 0 {
+		return 0
+	}
+	return gap - int(math.Round(float64(gap)*float64(pos)))
+}
+
+// JoinOffset return the offset of a block joined with gap free cells around it, like lipgloss.JoinHorizontal does
+func JoinOffset(gap int, pos lipgloss.Position) int {
+	if gap <= 0 {
+		return 0
+	}
+	return int(math.Round(float64(gap) * float64(pos)))
 }
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const appDir = "breach-protocol"
+
+// DataDir return the directory of the game data, $XDG_DATA_HOME/breach-protocol or ~/.local/share/breach-protocol
+func DataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, appDir), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error on finding home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", appDir), nil
+}
+
+// Read decode the json file of the data directory into v, v is left unchanged if the file does not exist
+func Read(name string, v any) error {
+	dir, err := DataDir()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error on reading %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("error on decoding %s: %w", name, err)
+	}
+	return nil
+}
+
+// Remove delete the file of the data directory, if it exists
+func Remove(name string) error {
+	dir, err := DataDir()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error on removing %s: %w", name, err)
+	}
+	return nil
+}
+
+// Write encode v as json in the file of the data directory
+func Write(name string, v any) error {
+	dir, err := DataDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("error on creating data directory: %w", err)
+	}
+	data, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return fmt.Errorf("error on encoding %s: %w", name, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("error on writing %s: %w", name, err)
+	}
+	return nil
+}